@@ -1,18 +1,46 @@
 #!/bin/bash
 # SPMD End-to-End Test Script
 # Tests progressive levels of SPMD compilation and execution
+#
+# Usage: spmd-e2e-test.sh [--simd-width=32|64|128|256|512|native]
+#   --simd-width builds the WASM levels at a virtual SIMD width (PLAN.md 2.9e).
+#   Native levels (10-12) pick their width from CPU features and are skipped.
+#   The script exits if tinygo does not accept -simd-width yet.
 set -uo pipefail
 
+SIMD_WIDTH="native"
+for arg in "$@"; do
+    case "$arg" in
+        --simd-width=*) SIMD_WIDTH="${arg#--simd-width=}" ;;
+        *) echo "usage: $0 [--simd-width=32|64|128|256|512|native]" >&2; exit 2 ;;
+    esac
+done
+case "$SIMD_WIDTH" in
+    32|64|128|256|512|native) ;;
+    *) echo "invalid --simd-width: $SIMD_WIDTH (want 32, 64, 128, 256, 512 or native)" >&2; exit 2 ;;
+esac
+
 SPMD_ROOT="$(cd "$(dirname "$0")/../.." && pwd)"
 TINYGO="$SPMD_ROOT/tinygo/build/tinygo"
 GOROOT_SPMD="$SPMD_ROOT/go"
 RUNNER="$SPMD_ROOT/test/e2e/run-wasm.mjs"
+INTEG="$SPMD_ROOT/test/integration/spmd"
 WASMOPT="${WASMOPT:-/tmp/wasm-opt}"
 # If wasm-opt binary doesn't exist, disable it so TinyGo doesn't fail
 if [ ! -x "$WASMOPT" ]; then
     WASMOPT=""
 fi
 OUTDIR="/tmp/spmd-e2e"
+SIMD_WIDTH_FLAG=""
+if [ "$SIMD_WIDTH" != "native" ]; then
+    OUTDIR="/tmp/spmd-e2e-w$SIMD_WIDTH"
+    SIMD_WIDTH_FLAG="-simd-width=$SIMD_WIDTH"
+fi
+
+# Expected outputs of these tests were written for 4 int32 lanes (128-bit).
+# At another virtual width they are only checked to compile and run; Level 8
+# (dual mode) still compares their SIMD and scalar output.
+WIDTH_DEPENDENT=" L2_lanes_index integ_array-counting integ_non-spmd-varying-return integ_debug-varying integ_lanes-index-restrictions integ_store-coalescing integ_defer-varying integ_panic-recover-varying integ_bit-counting "
 
 # Detect WASM runtime: prefer wasmtime over Node.js for better performance and stability.
 if command -v wasmtime &>/dev/null; then
@@ -24,7 +52,12 @@ fi
 mkdir -p "$OUTDIR"
 
 # Counters
-TOTAL=0; COMPILE_PASS=0; COMPILE_FAIL=0; RUN_PASS=0; RUN_FAIL=0; REJECT_PASS=0; REJECT_FAIL=0
+TOTAL=0; COMPILE_PASS=0; COMPILE_FAIL=0; RUN_PASS=0; RUN_FAIL=0; REJECT_PASS=0; REJECT_FAIL=0; SKIPPED=0
+
+# Examples for features that are specified in docs/superpowers/specs but not
+# yet implemented in the go and tinygo forks. Their tests are skipped unless
+# SPMD_E2E_PROPOSED=1; drop an entry when its feature lands.
+PROPOSED=" swizzle-rotate prefix-scan generic-spmd-calls spmd-export spmd-export-misuse ipv4-batch varying-struct reduce-semantics debug-dwarf varying-map-lookup histogram "
 
 # Colors
 RED='\033[0;31m'; GREEN='\033[0;32m'; YELLOW='\033[0;33m'; BLUE='\033[0;34m'; NC='\033[0m'
@@ -32,7 +65,16 @@ RED='\033[0;31m'; GREEN='\033[0;32m'; YELLOW='\033[0;33m'; BLUE='\033[0;34m'; NC
 compile() {
     local src="$1" out="$2" extra="${3:-}"
     WASMOPT="$WASMOPT" GOEXPERIMENT=spmd GOROOT="$GOROOT_SPMD" \
-        "$TINYGO" build -target=wasi $extra -o "$out" "$src" 2>&1
+        "$TINYGO" build -target=wasi $SIMD_WIDTH_FLAG $extra -o "$out" "$src" 2>&1
+}
+
+# tinygo_accepts_flag flags: false when tinygo rejects one of flags (or
+# $SIMD_WIDTH_FLAG) as undefined. Any other build result counts as accepted,
+# so a level gated on it reports its own failures.
+tinygo_accepts_flag() {
+    local probe
+    probe=$(compile "$INTEG/simple-sum/main.go" "$OUTDIR/flag_probe.wasm" "-scheduler=none $1")
+    ! grep -q "flag provided but not defined" <<<"$probe"
 }
 
 run_wasm() {
@@ -52,8 +94,25 @@ run_wasm() {
     fi
 }
 
+# proposed NAME SRC: true (after printing a SKIP line) when SRC is a proposed
+# example. SRC is a main.go (the example is its directory) or a single file.
+proposed() {
+    local name="$1" src="$2" example
+    [ "${SPMD_E2E_PROPOSED:-0}" = "1" ] && return 1
+    if [ "$(basename "$src")" = "main.go" ]; then
+        example=$(basename "$(dirname "$src")")
+    else
+        example=$(basename "$src" .go)
+    fi
+    [[ "$PROPOSED" == *" $example "* ]] || return 1
+    SKIPPED=$((SKIPPED + 1))
+    printf "${YELLOW}SKIP${NC}         %-40s %s\n" "$name" "(proposed, SPMD_E2E_PROPOSED=1 to run)"
+    return 0
+}
+
 test_compile() {
     local name="$1" src="$2" extra="${3:-}"
+    proposed "$name" "$src" && return 0
     local out="$OUTDIR/${name}.wasm"
     TOTAL=$((TOTAL + 1))
     local result
@@ -71,6 +130,7 @@ test_compile() {
 
 test_compile_and_run() {
     local name="$1" src="$2" expected="${3:-}" export="${4:-}" extra="${5:-}"
+    proposed "$name" "$src" && return 0
     local out="$OUTDIR/${name}.wasm"
     TOTAL=$((TOTAL + 1))
     local result
@@ -92,6 +152,9 @@ test_compile_and_run() {
     # Filter node warnings from output
     output=$(echo "$output" | grep -v "ExperimentalWarning\|trace-warnings")
 
+    if [ -n "$SIMD_WIDTH_FLAG" ] && [[ "$WIDTH_DEPENDENT" == *" $name "* ]]; then
+        expected=""
+    fi
     if [ -n "$expected" ]; then
         local match_mode="exact"
         local match_pattern="$expected"
@@ -145,6 +208,7 @@ compile_x86_avx2() {
 
 test_x86() {
     local name="$1" src="$2" expected="${3:-}" extra="${4:-}"
+    proposed "$name" "$src" && return 0
     local out="$OUTDIR/${name}"
     TOTAL=$((TOTAL + 1))
     local result
@@ -203,6 +267,7 @@ test_x86() {
 
 test_x86_avx2() {
     local name="$1" src="$2" expected="${3:-}" extra="${4:-}"
+    proposed "$name" "$src" && return 0
     local out="$OUTDIR/${name}"
     TOTAL=$((TOTAL + 1))
     local result
@@ -259,8 +324,88 @@ test_x86_avx2() {
     fi
 }
 
+# arm64 cross-compilation, executed under qemu-user (TinyGo Linux binaries are static).
+QEMU_AARCH64="${QEMU_AARCH64:-qemu-aarch64}"
+SVE_VQ="${SVE_VQ:-1 2 4}"
+
+compile_arm64() {
+    local src="$1" out="$2" extra="${3:-}"
+    PATH="$GOROOT_SPMD/bin:$PATH" GOEXPERIMENT=spmd GOOS=linux GOARCH=arm64 \
+        "$TINYGO" build $extra -o "$out" "$src" 2>&1
+}
+
+test_arm64() {
+    local name="$1" src="$2" expected="${3:-}" extra="${4:-}" cpu="${5:-}"
+    proposed "$name" "$src" && return 0
+    local out="$OUTDIR/${name}"
+    TOTAL=$((TOTAL + 1))
+    local result
+    if ! result=$(compile_arm64 "$src" "$out" "$extra" 2>&1); then
+        COMPILE_FAIL=$((COMPILE_FAIL + 1))
+        local err=$(echo "$result" | tail -3)
+        printf "${RED}COMPILE FAIL${NC} %-40s %s\n" "$name" "$err"
+        return 1
+    fi
+    COMPILE_PASS=$((COMPILE_PASS + 1))
+
+    local output
+    if ! output=$(timeout 60 "$QEMU_AARCH64" ${cpu:+-cpu "$cpu"} "$out" 2>&1); then
+        RUN_FAIL=$((RUN_FAIL + 1))
+        local err=$(echo "$output" | head -3)
+        printf "${YELLOW}RUN FAIL${NC}     %-40s %s\n" "$name" "$err"
+        return 1
+    fi
+
+    if [ -n "$expected" ]; then
+        local match_mode="exact"
+        local match_pattern="$expected"
+        if [[ "$expected" == contains:* ]]; then
+            match_mode="contains"
+            match_pattern="${expected#contains:}"
+        fi
+
+        local passed=false
+        if [ "$match_mode" = "exact" ]; then
+            [ "$output" = "$match_pattern" ] && passed=true
+        else
+            passed=true
+            while IFS= read -r needle; do
+                if ! echo "$output" | grep -qF "$needle"; then
+                    passed=false
+                    break
+                fi
+            done <<< "${match_pattern//|||/$'\n'}"
+        fi
+
+        if $passed; then
+            RUN_PASS=$((RUN_PASS + 1))
+            printf "${GREEN}PASS${NC}         %-40s %s\n" "$name" "(output verified)"
+        else
+            RUN_FAIL=$((RUN_FAIL + 1))
+            printf "${RED}WRONG OUTPUT${NC} %-40s\n" "$name"
+            echo "  expected: ${expected:0:80}"
+            echo "  got:      ${output:0:80}"
+            return 1
+        fi
+    else
+        RUN_PASS=$((RUN_PASS + 1))
+        printf "${GREEN}PASS${NC}         %-40s %s\n" "$name" "(no output check)"
+    fi
+}
+
+# SVE scalable mode: one build, run at every vector length in $SVE_VQ (128-bit units).
+test_arm64_sve() {
+    local name="$1" src="$2" expected="${3:-}"
+    local vq
+    for vq in $SVE_VQ; do
+        test_arm64 "${name}_vq${vq}" "$src" "$expected" \
+            "-llvm-features=+sve -simd-width=scalable" "max,sve=on,sve-max-vq=$vq"
+    done
+}
+
 test_compile_fail() {
     local name="$1" src="$2"
+    proposed "$name" "$src" && return 0
     TOTAL=$((TOTAL + 1))
     local out="$OUTDIR/${name}.wasm"
     local result
@@ -280,8 +425,15 @@ printf "${BLUE}=== SPMD End-to-End Test Suite ===${NC}\n"
 printf "TinyGo: %s\n" "$TINYGO"
 printf "GOROOT: %s\n" "$GOROOT_SPMD"
 printf "Runtime: %s\n" "$WASM_RUNTIME"
+printf "SIMD width: %s\n" "$SIMD_WIDTH"
 printf "Output: %s\n\n" "$OUTDIR"
 
+# -simd-width is planned (PLAN.md 2.9e) but not in the tinygo fork yet.
+if [ -n "$SIMD_WIDTH_FLAG" ] && ! tinygo_accepts_flag ""; then
+    printf "${RED}tinygo does not accept %s yet (PLAN.md 2.9e)${NC}\n" "$SIMD_WIDTH_FLAG" >&2
+    exit 2
+fi
+
 # ========== LEVEL 0: Minimal SPMD (no imports) ==========
 printf "${BLUE}--- Level 0: Minimal SPMD (no lane/reduce imports) ---${NC}\n"
 
@@ -628,7 +780,6 @@ test_compile_and_run "L5g_compound_conditions" "$OUTDIR/L5g_compound_conditions.
 # ========== LEVEL 5c: Integration test examples ==========
 printf "\n${BLUE}--- Level 5c: Integration examples (compile only) ---${NC}\n"
 
-INTEG="$SPMD_ROOT/test/integration/spmd"
 test_compile_and_run "integ_map-restrictions" "$INTEG/map-restrictions/main.go" \
     "contains:Map restrictions demonstration completed" "" "-scheduler=none"
 
@@ -679,6 +830,9 @@ test_compile_and_run "integ_store-coalescing" "$INTEG/store-coalescing/main.go"
 test_compile_and_run "integ_ipv4-parser"      "$INTEG/ipv4-parser/main.go" \
     "contains:'192.168.1.1' -> 192.168.1.1|||'127.0.0.1' -> 127.0.0.1|||'192.168.1.a' -> ERROR: parse 192.168.1.a at position 10: unexpected character|||'256.1.1.1' -> ERROR: parse 256.1.1.1 at position 0: IPv4 field has value >255|||'192.168.01.1' -> ERROR: parse 192.168.01.1 at position 0: IPv4 field has octet with leading zero" \
     "" "-scheduler=none"
+test_compile_and_run "integ_ipv4-batch"       "$INTEG/ipv4-batch/main.go" \
+    "contains:'192.168.1.1' -> 192.168.1.1|||'192.168.1.a' -> ERROR: unexpected character|||'256.1.1.1' -> ERROR: field > 255|||'192.168.01.1' -> ERROR: leading zero|||Correctness: PASS" \
+    "" "-scheduler=none"
 test_compile_and_run "integ_type-switch-varying" "$INTEG/type-switch-varying/main.go" \
     "contains:Varying int: sum=336|||Assert ok: sum=208|||All type switch varying tests completed" \
     "" "-scheduler=none"
@@ -699,12 +853,35 @@ test_compile_and_run "integ_lo-contains" "$INTEG/lo-contains/main.go" "contains:
 test_compile_and_run "integ_lo-clamp"    "$INTEG/lo-clamp/main.go"    "contains:Correctness: PASS" "" "-scheduler=none"
 test_compile_and_run "integ_pointer-varying" "$INTEG/pointer-varying/main.go" "contains:Correctness: PASS" "" "-scheduler=none"
 test_compile_and_run "integ_swizzle-within" "$INTEG/swizzle-within/main.go" "contains:Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_swizzle-rotate" "$INTEG/swizzle-rotate/main.go" "contains:Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_prefix-scan" "$INTEG/prefix-scan/main.go" "contains:Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_generic-spmd-calls" "$INTEG/generic-spmd-calls/main.go" "contains:Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_varying-struct" "$INTEG/varying-struct/main.go" \
+    "contains:lengthSq[0:4]: [185 25 13 149]|||Centroid sum: {0 -9}|||Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_shifted-load" "$INTEG/shifted-load/main.go" \
+    "contains:hexEncode: 53686966746564206c6f61647321|||Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_gather-coalesce" "$INTEG/gather-coalesce/main.go" \
+    "contains:fields[0:6]: [192 800 554 921 1 543]|||Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_reduce-semantics" "$INTEG/reduce-semantics/main.go" \
+    "contains:Add=396 Mul=262144 Min=-20 Max=40|||Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_varying-map-lookup" "$INTEG/varying-map-lookup/main.go" \
+    "contains:lookupIDs: [1 2 3 -1 4 -1 -1 5 1]|||terrain: ##?.?~~|||Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_histogram" "$INTEG/histogram/main.go" \
+    "contains:counts[abdinps]: [11 4 2 4 4 2 4]|||tickets: [1 1 2 3 1 2 3 4 2 5 4]|||Correctness: PASS" "" "-scheduler=none"
+# spmd-export imports a sibling package, so it is built as a package from the module root
+pushd "$INTEG" >/dev/null
+test_compile_and_run "integ_spmd-export" "./spmd-export" "contains:Correctness: PASS" "" "-scheduler=none"
+popd >/dev/null
 test_compile_and_run "integ_base64-decoder" "$INTEG/base64-decoder/main.go" \
     "contains:'SGVsbG8gV29ybGQ=' -> 'Hello World'" "" "-scheduler=none"
 test_compile_and_run "integ_base64-mula-lemire" "$INTEG/base64-mula-lemire/main.go" \
     "contains:Correctness: PASS" "" "-scheduler=none"
 test_compile_and_run "integ_pmaddubsw-pattern" "$INTEG/pmaddubsw-pattern/main.go" \
     "contains:Correctness: PASS" "" "-scheduler=none"
+test_compile_and_run "integ_debug-dwarf" "$INTEG/debug-dwarf/main.go" \
+    "contains:sumBig: 660|||farthest: 113|||sumClamped: 14" "" "-scheduler=none"
+test_compile_and_run "integ_spmd-remarks" "$INTEG/spmd-remarks/main.go" \
+    "contains:magnitude: [3 1 4 1 5 9 2 6 5 3]|||cycle: abcdabcdab" "" "-scheduler=none"
 
 # ========== LEVEL 7: Illegal examples (should fail) ==========
 printf "\n${BLUE}--- Level 7: Illegal examples (should be rejected) ---${NC}\n"
@@ -716,11 +893,290 @@ for f in "$ILLEGAL"/*.go; do
     test_compile_fail "illegal_$name" "$f"
 done
 
+# ========== LEVEL 7b: spmdvet diagnostics ==========
+# Legal code that the SPMD vet analyzers should report (vet-spmd/), and
+# examples that follow the recommended patterns and must stay clean. Build
+# spmdvet with `make build-spmdvet`; the level is skipped when it is missing.
+SPMDVET="${SPMDVET:-$SPMD_ROOT/x-tools-spmd/build/spmdvet}"
+if [ -x "$SPMDVET" ]; then
+
+printf "\n${BLUE}--- Level 7b: spmdvet diagnostics ---${NC}\n"
+
+# run_vet src [analyzer flags...]: go vet output for one file, run from its
+# directory so diagnostics are reported as "./file.go:line:col: message".
+run_vet() {
+    local src="$1"; shift
+    (cd "$(dirname "$src")" && GOEXPERIMENT=spmd GOROOT="$GOROOT_SPMD" \
+        "$GOROOT_SPMD/bin/go" vet -vettool="$SPMDVET" "$@" "./$(basename "$src")" 2>&1)
+}
+
+# vet_lines src output: "line: message" for each diagnostic in src.
+vet_lines() {
+    local file
+    file=$(basename "$1")
+    echo "$2" | sed -n "s|^\./$file:\([0-9]*\):[0-9]*: |\1: |p"
+}
+
+test_vet() {
+    local name="$1" src="$2"
+    local analyzer
+    analyzer=$(basename "$src" .go)
+    TOTAL=$((TOTAL + 1))
+
+    local output got
+    output=$(run_vet "$src" "-$analyzer")
+    got=$(vet_lines "$src" "$output")
+
+    local failures="" line msg
+    while IFS=: read -r line msg; do
+        [ -n "$line" ] || continue
+        if ! echo "$got" | grep "^$line: " | grep -qF "$msg"; then
+            failures="${failures}missing $line: $msg"$'\n'
+        fi
+    done < <(sed -n 's|^\(.*\)// WANT "\(.*\)"$|\2|;T;=;p' "$src" | paste -d: - -)
+
+    local want_lines extra
+    want_lines=$(grep -n '// WANT "' "$src" | cut -d: -f1)
+    extra=$(echo "$got" | grep -v "^$" | grep -vE "^($(echo $want_lines | tr ' ' '|')): ")
+    if [ -n "$extra" ]; then
+        failures="${failures}unexpected ${extra}"$'\n'
+    fi
+
+    if [ -z "$failures" ]; then
+        REJECT_PASS=$((REJECT_PASS + 1))
+        printf "${GREEN}VET OK${NC}       %-40s\n" "$name"
+    else
+        REJECT_FAIL=$((REJECT_FAIL + 1))
+        printf "${RED}VET FAIL${NC}     %-40s\n" "$name"
+        printf "%s" "$failures" | head -5
+        [ -n "$got" ] || echo "$output" | head -5
+    fi
+}
+
+test_vet_clean() {
+    local name="$1" src="$2"
+    proposed "$name" "$src" && return 0
+    TOTAL=$((TOTAL + 1))
+
+    local output
+    if output=$(run_vet "$src"); then
+        REJECT_PASS=$((REJECT_PASS + 1))
+        printf "${GREEN}VET OK${NC}       %-40s\n" "$name"
+    else
+        REJECT_FAIL=$((REJECT_FAIL + 1))
+        printf "${RED}VET FAIL${NC}     %-40s %s\n" "$name" "unexpected diagnostics"
+        echo "$output" | head -5
+    fi
+}
+
+for f in "$INTEG"/vet-spmd/*.go; do
+    [ -f "$f" ] || continue
+    test_vet "vet_$(basename "$f" .go)" "$f"
+done
+
+for ex in simple-sum odd-even to-upper lo-sum lo-mean lo-min lo-max lo-contains lo-clamp prefix-scan; do
+    test_vet_clean "vet_clean_$ex" "$INTEG/$ex/main.go"
+done
+
+fi  # spmdvet check
+
+# ========== LEVEL 7c: gopls diagnostics ==========
+# The editor must show what the build shows: every line the compiler rejects
+# in an illegal example is a gopls diagnostic, and run-pass examples have no
+# diagnostics at all. Build gopls with `make build-gopls`; the level is
+# skipped when it is missing.
+GOPLS="${GOPLS:-$SPMD_ROOT/x-tools-spmd/build/gopls}"
+if [ -x "$GOPLS" ]; then
+
+printf "\n${BLUE}--- Level 7c: gopls diagnostics (gopls check) ---${NC}\n"
+
+GOPLS_ENV=(GOEXPERIMENT=spmd GOROOT="$GOROOT_SPMD" PATH="$GOROOT_SPMD/bin:$PATH" GOOS=wasip1 GOARCH=wasm)
+if [ "$SIMD_WIDTH" != "native" ]; then
+    GOPLS_ENV+=(SPMD_WIDTH="$SIMD_WIDTH")
+fi
+
+run_gopls_check() {
+    local src="$1"
+    (cd "$INTEG" && env "${GOPLS_ENV[@]}" "$GOPLS" check "$src" 2>&1)
+}
+
+# error_lines file output: the line numbers of file in output, sorted for comm.
+error_lines() {
+    echo "$2" | grep -o "$(basename "$1"):[0-9]*" | cut -d: -f2 | sort -u
+}
+
+test_gopls_reject() {
+    local name="$1" src="$2"
+    proposed "$name" "$src" && return 0
+    TOTAL=$((TOTAL + 1))
+
+    local compiled diags missing
+    compiled=$(compile "$src" "$OUTDIR/${name}.wasm")
+    diags=$(run_gopls_check "$src")
+    missing=$(comm -23 <(error_lines "$src" "$compiled") <(error_lines "$src" "$diags") | tr '\n' ' ')
+
+    if [ -z "$(error_lines "$src" "$diags")" ]; then
+        REJECT_FAIL=$((REJECT_FAIL + 1))
+        printf "${RED}GOPLS FAIL${NC}   %-40s %s\n" "$name" "no diagnostics"
+    elif [ -n "$missing" ]; then
+        REJECT_FAIL=$((REJECT_FAIL + 1))
+        printf "${RED}GOPLS FAIL${NC}   %-40s %s\n" "$name" "compiler errors not reported on lines: $missing"
+    else
+        REJECT_PASS=$((REJECT_PASS + 1))
+        printf "${GREEN}GOPLS OK${NC}     %-40s\n" "$name"
+    fi
+}
+
+test_gopls_clean() {
+    local name="$1" src="$2"
+    proposed "$name" "$src" && return 0
+    TOTAL=$((TOTAL + 1))
+
+    local diags
+    diags=$(run_gopls_check "$src")
+    if [ -z "$diags" ]; then
+        REJECT_PASS=$((REJECT_PASS + 1))
+        printf "${GREEN}GOPLS OK${NC}     %-40s\n" "$name"
+    else
+        REJECT_FAIL=$((REJECT_FAIL + 1))
+        printf "${RED}GOPLS FAIL${NC}   %-40s %s\n" "$name" "unexpected diagnostics"
+        echo "$diags" | head -5
+    fi
+}
+
+for f in "$ILLEGAL"/*.go; do
+    [ -f "$f" ] || continue
+    test_gopls_reject "gopls_$(basename "$f" .go)" "$f"
+done
+
+# The width-independent examples of Level 17: legal at every width.
+for ex in simple-sum odd-even printf-verbs to-upper lo-sum lo-mean lo-min lo-max lo-contains lo-clamp \
+        shifted-load gather-coalesce spmd-remarks swizzle-rotate prefix-scan generic-spmd-calls ipv4-batch varying-struct \
+        reduce-semantics debug-dwarf varying-map-lookup histogram; do
+    test_gopls_clean "gopls_clean_$ex" "$INTEG/$ex/main.go"
+done
+
+fi  # gopls check
+
+# ========== LEVEL 7d: gofmt round trip ==========
+# gofmt must keep every go for a go for: the golden pairs in testdata/gofmt
+# cover each form, and every example is checked to keep its go for loops, to
+# be stable under gofmt and under an identity gofmt -r, and (for the
+# width-independent examples) to print the same output once formatted.
+GOFMT="$GOROOT_SPMD/bin/gofmt"
+if [ -x "$GOFMT" ]; then
+
+printf "\n${BLUE}--- Level 7d: gofmt round trip ---${NC}\n"
+
+run_gofmt() {
+    GOEXPERIMENT=spmd "$GOFMT" "$@" 2>&1
+}
+
+count_go_for() {
+    echo "$1" | grep -o '\bgo \+for\b' | wc -l
+}
+
+gofmt_result() {
+    local name="$1" failure="$2"
+    if [ -z "$failure" ]; then
+        RUN_PASS=$((RUN_PASS + 1))
+        printf "${GREEN}GOFMT OK${NC}     %-40s\n" "$name"
+    else
+        RUN_FAIL=$((RUN_FAIL + 1))
+        printf "${RED}GOFMT FAIL${NC}   %-40s %s\n" "$name" "$failure"
+    fi
+}
+
+# test_gofmt_golden name input: gofmt with the flags on the input's
+# "//gofmt" first line must print the .golden file and leave it unchanged.
+test_gofmt_golden() {
+    local name="$1" input="$2"
+    local golden="${input%.input}.golden"
+    TOTAL=$((TOTAL + 1))
+
+    local flags=()
+    read -ra flags <<< "$(sed -n '1s|^//gofmt ||p' "$input")"
+    local got again failure=""
+    got=$(run_gofmt "${flags[@]}" "$input")
+    again=$(run_gofmt "${flags[@]}" "$golden")
+    if [ "$got" != "$(cat "$golden")" ]; then
+        failure="output differs from $(basename "$golden")"
+        diff <(echo "$got") "$golden" | head -5
+    elif [ "$again" != "$got" ]; then
+        failure="$(basename "$golden") is not stable"
+    fi
+    gofmt_result "$name" "$failure"
+}
+
+test_gofmt_stable() {
+    local name="$1" src="$2"
+    proposed "$name" "$src" && return 0
+    TOTAL=$((TOTAL + 1))
+
+    local formatted rewritten again failure=""
+    if ! formatted=$(run_gofmt "$src"); then
+        gofmt_result "$name" "gofmt failed: $(echo "$formatted" | head -1)"
+        return 1
+    fi
+    rewritten=$(run_gofmt -r 'a -> a' "$src")
+    again=$(echo "$formatted" | run_gofmt)
+    if [ "$(count_go_for "$formatted")" != "$(count_go_for "$(cat "$src")")" ]; then
+        failure="go for count changed: $(count_go_for "$(cat "$src")") -> $(count_go_for "$formatted")"
+    elif [ "$rewritten" != "$formatted" ]; then
+        failure="gofmt -r 'a -> a' differs from gofmt"
+    elif [ "$again" != "$formatted" ]; then
+        failure="gofmt output is not stable"
+    fi
+    gofmt_result "$name" "$failure"
+}
+
+test_gofmt_run() {
+    local name="$1" src="$2"
+    proposed "$name" "$src" && return 0
+    TOTAL=$((TOTAL + 1))
+
+    local dir="$OUTDIR/gofmt/$name"
+    mkdir -p "$dir"
+    run_gofmt "$src" > "$dir/main.go"
+    if ! compile "$src" "$dir/orig.wasm" "-scheduler=none" >/dev/null 2>&1 ||
+       ! compile "$dir/main.go" "$dir/fmt.wasm" "-scheduler=none" >/dev/null 2>&1; then
+        gofmt_result "$name" "compile failed"
+        return 1
+    fi
+    local filter="ExperimentalWarning\|trace-warnings\|^Scalar:\|^SPMD:\|^Speedup:"
+    if [ "$(run_wasm "$dir/orig.wasm" | grep -v "$filter")" != "$(run_wasm "$dir/fmt.wasm" | grep -v "$filter")" ]; then
+        gofmt_result "$name" "formatted program prints different output"
+    else
+        gofmt_result "$name" ""
+    fi
+}
+
+for f in "$INTEG"/testdata/gofmt/*.input; do
+    [ -f "$f" ] || continue
+    test_gofmt_golden "gofmt_$(basename "$f" .input)" "$f"
+done
+
+for f in "$INTEG"/*/main.go "$INTEG"/vet-spmd/*.go; do
+    [ -f "$f" ] || continue
+    name=$(basename "$f" .go)
+    [ "$name" = "main" ] && name=$(basename "$(dirname "$f")")
+    test_gofmt_stable "gofmt_$name" "$f"
+done
+
+for ex in simple-sum odd-even printf-verbs to-upper lo-sum lo-mean lo-min lo-max lo-contains lo-clamp \
+        shifted-load gather-coalesce spmd-remarks swizzle-rotate prefix-scan generic-spmd-calls ipv4-batch varying-struct \
+        reduce-semantics debug-dwarf varying-map-lookup histogram; do
+    test_gofmt_run "gofmt_run_$ex" "$INTEG/$ex/main.go"
+done
+
+fi  # gofmt check
+
 # ========== LEVEL 8: Dual-mode testing (SIMD vs scalar) ==========
 printf "\n${BLUE}--- Level 8: Dual-mode (SIMD vs scalar) ---${NC}\n"
 
 test_dual_mode() {
     local name="$1" src="$2" extra="${3:--scheduler=none}"
+    proposed "$name" "$src" && return 0
     local simd_out="$OUTDIR/${name}-simd.wasm"
     local scalar_out="$OUTDIR/${name}-scalar.wasm"
     TOTAL=$((TOTAL + 1))
@@ -771,6 +1227,18 @@ test_dual_mode "dual_lo-min"           "$INTEG/lo-min/main.go"
 test_dual_mode "dual_lo-max"           "$INTEG/lo-max/main.go"
 test_dual_mode "dual_lo-contains"      "$INTEG/lo-contains/main.go"
 test_dual_mode "dual_lo-clamp"         "$INTEG/lo-clamp/main.go"
+test_dual_mode "dual_prefix-scan"      "$INTEG/prefix-scan/main.go"
+test_dual_mode "dual_generic-spmd-calls" "$INTEG/generic-spmd-calls/main.go"
+test_dual_mode "dual_ipv4-batch"       "$INTEG/ipv4-batch/main.go"
+test_dual_mode "dual_varying-struct"   "$INTEG/varying-struct/main.go"
+test_dual_mode "dual_shifted-load"     "$INTEG/shifted-load/main.go"
+test_dual_mode "dual_gather-coalesce"  "$INTEG/gather-coalesce/main.go"
+test_dual_mode "dual_reduce-semantics" "$INTEG/reduce-semantics/main.go"
+test_dual_mode "dual_varying-map-lookup" "$INTEG/varying-map-lookup/main.go"
+test_dual_mode "dual_histogram" "$INTEG/histogram/main.go"
+pushd "$INTEG" >/dev/null
+test_dual_mode "dual_spmd-export"      "./spmd-export"
+popd >/dev/null
 
 # ========== LEVEL 9: Lane-count-dependent scalar validation ==========
 printf "\n${BLUE}--- Level 9: Scalar validation (lane-count-dependent tests) ---${NC}\n"
@@ -811,12 +1279,14 @@ test_compile_and_run "scalar_printf-verbs"              "$INTEG/printf-verbs/mai
     "contains:No '%' found in: No verbs here" "" "-scheduler=none -simd=false"
 test_compile_and_run "scalar_array-counting"            "$INTEG/array-counting/main.go" \
     "contains:Array sums:" "" "-scheduler=none -simd=false"
+test_compile_and_run "scalar_swizzle-rotate"            "$INTEG/swizzle-rotate/main.go" \
+    "contains:Correctness: PASS" "" "-scheduler=none -simd=false"
 # pointer-varying: lane-count-dependent logic (lanes.Index per-lane), fails its
 # own correctness check in scalar mode. Compile-only validation.
 test_compile "scalar_pointer-varying" "$INTEG/pointer-varying/main.go" "-simd=false"
 
 # ========== LEVEL 10 & 11: x86-64 native (SSE and AVX2) ==========
-if [ "$(uname -m)" = "x86_64" ]; then
+if [ "$(uname -m)" = "x86_64" ] && [ -z "$SIMD_WIDTH_FLAG" ]; then
 
 # ========== LEVEL 10: x86-64 native SSE (128-bit, 4-wide i32) ==========
 printf "\n${BLUE}--- Level 10: x86-64 native SSE (128-bit, 4-wide i32) ---${NC}\n"
@@ -831,9 +1301,101 @@ test_x86 "x86_to-upper"    "$INTEG/to-upper/main.go"    "contains:'hello world'
 test_x86 "x86_mandelbrot" "$INTEG/mandelbrot/main.go"  "contains:Mandelbrot SPMD example completed successfully"
 test_x86 "x86_simple-sum" "$INTEG/simple-sum/main.go"  "Sum: 136"
 test_x86 "x86_odd-even"   "$INTEG/odd-even/main.go"    "Result: Odd=4, Even=4"
+test_x86 "x86_swizzle-rotate" "$INTEG/swizzle-rotate/main.go" "contains:Correctness: PASS"
+test_x86 "x86_prefix-scan" "$INTEG/prefix-scan/main.go" "contains:Correctness: PASS"
+test_x86 "x86_generic-spmd-calls" "$INTEG/generic-spmd-calls/main.go" "contains:Correctness: PASS"
+test_x86 "x86_ipv4-batch" "$INTEG/ipv4-batch/main.go" "contains:Correctness: PASS"
+test_x86 "x86_varying-struct" "$INTEG/varying-struct/main.go" "contains:Correctness: PASS"
+test_x86 "x86_shifted-load" "$INTEG/shifted-load/main.go" "contains:Correctness: PASS"
+test_x86 "x86_gather-coalesce" "$INTEG/gather-coalesce/main.go" "contains:Correctness: PASS"
+test_x86 "x86_reduce-semantics" "$INTEG/reduce-semantics/main.go" "contains:Correctness: PASS"
+test_x86 "x86_varying-map-lookup" "$INTEG/varying-map-lookup/main.go" "contains:Correctness: PASS"
+test_x86 "x86_histogram" "$INTEG/histogram/main.go" "contains:Correctness: PASS"
+pushd "$INTEG" >/dev/null
+test_x86 "x86_spmd-export" "./spmd-export" "contains:Correctness: PASS"
+popd >/dev/null
 # store-coalescing: lane-count-dependent interleaving, wrong output on x86 native (under investigation)
 # hex-encode: SIGSEGV on x86-64 native (known issue)
 
+# ========== LEVEL 10b: x86-64 native debugging (gdb) ==========
+# TinyGo's DWARF for varying variables and the execution mask
+# (docs/superpowers/specs/2026-10-16-spmd-dwarf-design.md), read by gdb with
+# tools/debug/spmd_gdb.py loaded. Every "// GDB" command in debug-dwarf runs
+# at the first stop on its line and must print its expected text. Skipped
+# when gdb is not installed.
+if command -v gdb &>/dev/null; then
+
+printf "\n${BLUE}--- Level 10b: x86-64 native debugging (gdb) ---${NC}\n"
+
+# gdb_markers src: "line<TAB>command<TAB>want" for each pair in the "// GDB"
+# comments of src.
+gdb_markers() {
+    grep -n '// GDB "' "$1" | while IFS=: read -r line rest; do
+        echo "${rest#*// GDB }" | grep -o '"[^"]*"' | tr -d '"' | paste - - | sed "s/^/$line\t/"
+    done
+}
+
+test_gdb() {
+    local name="$1" src="$2"
+    proposed "$name" "$src" && return 0
+    local out="$OUTDIR/${name}" cmds="$OUTDIR/${name}.gdb"
+    TOTAL=$((TOTAL + 1))
+    local result
+    if ! result=$(compile_x86 "$src" "$out" "-opt=1" 2>&1); then
+        COMPILE_FAIL=$((COMPILE_FAIL + 1))
+        printf "${RED}COMPILE FAIL${NC} %-40s %s\n" "$name" "$(echo "$result" | tail -3)"
+        return 1
+    fi
+    COMPILE_PASS=$((COMPILE_PASS + 1))
+
+    # One breakpoint per marked line. At its first stop it echoes "@@line:k"
+    # before the k-th command of the file, then disables itself.
+    local markers file line bp=0
+    markers=$(gdb_markers "$src")
+    file=$(basename "$src")
+    {
+        echo "set pagination off"
+        echo "set confirm off"
+        echo "source $SPMD_ROOT/tools/debug/spmd_gdb.py"
+        for line in $(echo "$markers" | cut -f1 | uniq); do
+            bp=$((bp + 1))
+            echo "break $file:$line"
+            echo "commands"
+            echo "silent"
+            echo "$markers" | awk -F'\t' -v l="$line" '$1 == l { printf "echo @@%s:%d\\n\n%s\n", l, NR, $2 }'
+            echo "disable $bp"
+            echo "continue"
+            echo "end"
+        done
+        echo "run"
+    } > "$cmds"
+
+    local output
+    output=$(timeout 60 gdb -batch -nx -x "$cmds" "$out" 2>&1)
+
+    local failures="" k=0 cmd want section
+    while IFS=$'\t' read -r line cmd want; do
+        k=$((k + 1))
+        section=$(echo "$output" | awk -v m="@@$line:$k" '$0 == m { on = 1; next } /^@@/ { on = 0 } on')
+        if ! echo "$section" | grep -qF -- "$want"; then
+            failures="${failures}$file:$line: $cmd: want '$want', got '$(echo "$section" | head -1)'"$'\n'
+        fi
+    done <<< "$markers"
+
+    if [ -z "$failures" ]; then
+        RUN_PASS=$((RUN_PASS + 1))
+        printf "${GREEN}GDB OK${NC}       %-40s\n" "$name"
+    else
+        RUN_FAIL=$((RUN_FAIL + 1))
+        printf "${RED}GDB FAIL${NC}     %-40s\n" "$name"
+        printf "%s" "$failures" | head -5
+    fi
+}
+
+test_gdb "gdb_debug-dwarf" "$INTEG/debug-dwarf/main.go"
+
+fi  # gdb check
+
 # ========== LEVEL 11: x86-64 native AVX2 (256-bit, 8-wide i32) ==========
 printf "\n${BLUE}--- Level 11: x86-64 native AVX2 (256-bit, 8-wide i32) ---${NC}\n"
 
@@ -847,9 +1409,365 @@ test_x86_avx2 "avx2_simple-sum"  "$INTEG/simple-sum/main.go"  "Sum: 136"
 test_x86_avx2 "avx2_odd-even"    "$INTEG/odd-even/main.go"    "Result: Odd=4, Even=4"
 test_x86_avx2 "avx2_to-upper"    "$INTEG/to-upper/main.go"    "contains:'hello world' -> 'HELLO WORLD'"
 test_x86_avx2 "avx2_mandelbrot" "$INTEG/mandelbrot/main.go"  "contains:Mandelbrot SPMD example completed successfully"
+# swizzle-rotate: runtime indices must cross the 128-bit halves of 32-lane byte vectors
+test_x86_avx2 "avx2_swizzle-rotate" "$INTEG/swizzle-rotate/main.go" "contains:Correctness: PASS"
+test_x86_avx2 "avx2_prefix-scan" "$INTEG/prefix-scan/main.go" "contains:Correctness: PASS"
+test_x86_avx2 "avx2_generic-spmd-calls" "$INTEG/generic-spmd-calls/main.go" "contains:Correctness: PASS"
+test_x86_avx2 "avx2_ipv4-batch" "$INTEG/ipv4-batch/main.go" "contains:Correctness: PASS"
+test_x86_avx2 "avx2_varying-struct" "$INTEG/varying-struct/main.go" "contains:Correctness: PASS"
+test_x86_avx2 "avx2_shifted-load" "$INTEG/shifted-load/main.go" "contains:Correctness: PASS"
+test_x86_avx2 "avx2_gather-coalesce" "$INTEG/gather-coalesce/main.go" "contains:Correctness: PASS"
+test_x86_avx2 "avx2_reduce-semantics" "$INTEG/reduce-semantics/main.go" "contains:Correctness: PASS"
+test_x86_avx2 "avx2_varying-map-lookup" "$INTEG/varying-map-lookup/main.go" "contains:Correctness: PASS"
+test_x86_avx2 "avx2_histogram" "$INTEG/histogram/main.go" "contains:Correctness: PASS"
+pushd "$INTEG" >/dev/null
+test_x86_avx2 "avx2_spmd-export" "./spmd-export" "contains:Correctness: PASS"
+popd >/dev/null
 
 fi  # x86_64 check
 
+# ========== LEVEL 12: arm64 NEON (qemu-user) ==========
+# The arm64 backend is not implemented yet; enable with SPMD_E2E_ARM64=1.
+if [ "${SPMD_E2E_ARM64:-0}" = "1" ] && command -v "$QEMU_AARCH64" &>/dev/null && [ -z "$SIMD_WIDTH_FLAG" ]; then
+
+printf "\n${BLUE}--- Level 12: arm64 NEON (128-bit, qemu-user) ---${NC}\n"
+
+test_arm64 "arm64_lo-sum"      "$INTEG/lo-sum/main.go"      "contains:Correctness: PASS"
+test_arm64 "arm64_lo-mean"     "$INTEG/lo-mean/main.go"     "contains:Correctness: PASS"
+test_arm64 "arm64_lo-min"      "$INTEG/lo-min/main.go"      "contains:Correctness: PASS"
+test_arm64 "arm64_lo-max"      "$INTEG/lo-max/main.go"      "contains:Correctness: PASS"
+test_arm64 "arm64_lo-contains" "$INTEG/lo-contains/main.go" "contains:Correctness: PASS"
+test_arm64 "arm64_lo-clamp"    "$INTEG/lo-clamp/main.go"    "contains:Correctness: PASS"
+test_arm64 "arm64_to-upper"    "$INTEG/to-upper/main.go"    "contains:'hello world' -> 'HELLO WORLD'"
+test_arm64 "arm64_mandelbrot"  "$INTEG/mandelbrot/main.go"  "contains:Mandelbrot SPMD example completed successfully"
+test_arm64 "arm64_simple-sum"  "$INTEG/simple-sum/main.go"  "Sum: 136"
+test_arm64 "arm64_odd-even"    "$INTEG/odd-even/main.go"    "Result: Odd=4, Even=4"
+test_arm64 "arm64_hex-encode"  "$INTEG/hex-encode/main.go"  "contains:Correctness: SPMD and Scalar results match."
+test_arm64 "arm64_base64-decoder" "$INTEG/base64-decoder/main.go" "contains:'SGVsbG8gV29ybGQ=' -> 'Hello World'"
+
+# ========== LEVEL 12b: arm64 SVE scalable vectors (qemu-user, opt-in) ==========
+# Scalable mode (lanes.Count is a runtime value) is not implemented yet; enable with SPMD_E2E_SVE=1.
+if [ "${SPMD_E2E_SVE:-0}" = "1" ]; then
+printf "\n${BLUE}--- Level 12b: arm64 SVE (scalable, vq=%s) ---${NC}\n" "$SVE_VQ"
+
+test_arm64_sve "sve_simple-sum"  "$INTEG/simple-sum/main.go"  "Sum: 136"
+test_arm64_sve "sve_odd-even"    "$INTEG/odd-even/main.go"    "Result: Odd=4, Even=4"
+test_arm64_sve "sve_lo-sum"      "$INTEG/lo-sum/main.go"      "contains:Correctness: PASS"
+test_arm64_sve "sve_lo-min"      "$INTEG/lo-min/main.go"      "contains:Correctness: PASS"
+test_arm64_sve "sve_lo-max"      "$INTEG/lo-max/main.go"      "contains:Correctness: PASS"
+test_arm64_sve "sve_lo-contains" "$INTEG/lo-contains/main.go" "contains:Correctness: PASS"
+fi  # SPMD_E2E_SVE
+
+fi  # SPMD_E2E_ARM64 and qemu-aarch64 check
+
+# ========== LEVEL 15: SPMD sanitizer ==========
+# tinygo -spmd-sanitize checks scatters for lanes that store to the same
+# address, and active lanes for out-of-range indices and compact stores past
+# capacity (docs/superpowers/specs/2026-10-16-spmd-sanitizer-design.md). Each
+# program in spmd-sanitize/ runs under the WASM runtime, and natively on
+# x86-64. Every "// SANITIZE" string must be in a report that points at its
+# line, with no other report. The markers were written for 4 int32 lanes; the
+# level is skipped at other widths and when tinygo does not know the flag.
+SANITIZE_DIR="$INTEG/spmd-sanitize"
+if [ -z "$SIMD_WIDTH_FLAG" ] && tinygo_accepts_flag "-spmd-sanitize"; then
+
+printf "\n${BLUE}--- Level 15: SPMD sanitizer (-spmd-sanitize) ---${NC}\n"
+
+# sanitize_check src output status want: check a sanitized run of src. Its
+# reports must match the "// SANITIZE" markers, and its exit status must be
+# want: a number, or "panic" for a nonzero status after a Go panic.
+sanitize_check() {
+    local src="$1" output="$2" status="$3" want="$4"
+    local file failures="" line text reports
+    file=$(basename "$src")
+    # One line per report: the lines between a pair of "=================="
+    # lines, joined.
+    reports=$(echo "$output" | awk '/^==================$/ { if (on) print r; r = ""; on = !on; next } on { r = r " " $0 }')
+    while IFS=: read -r line text; do
+        if ! echo "$reports" | grep -E "(^|[ /])$file:$line:[0-9]+" | grep -qF "$text"; then
+            failures="${failures}no report at $file:$line: $text"$'\n'
+        fi
+    done < <(grep -n '// SANITIZE "' "$src" | while IFS=: read -r line rest; do
+        echo "${rest#*// SANITIZE }" | grep -o '"[^"]*"' | tr -d '"' | sed "s/^/$line:/"
+    done)
+
+    local markers count
+    markers=$(grep -c '// SANITIZE "' "$src")
+    count=$(echo "$reports" | grep -c "SPMD SANITIZER")
+    if [ "$count" != "$markers" ]; then
+        failures="${failures}$count reports for $markers markers"$'\n'
+    fi
+
+    if [ "$want" = "panic" ]; then
+        if [ "$status" = 0 ] || ! echo "$output" | grep -q "panic: .*index out of range"; then
+            failures="${failures}want an index out of range panic, got status $status"$'\n'
+        fi
+    elif [ "$status" != "$want" ]; then
+        failures="${failures}want exit status $want, got $status"$'\n'
+    fi
+    printf "%s" "$failures"
+}
+
+# sanitize_result name failures: count and print the result of one run.
+sanitize_result() {
+    local name="$1" failures="$2"
+    if [ -z "$failures" ]; then
+        RUN_PASS=$((RUN_PASS + 1))
+        printf "${GREEN}SANITIZE OK${NC}  %-40s\n" "$name"
+    else
+        RUN_FAIL=$((RUN_FAIL + 1))
+        printf "${RED}SANITIZE FAIL${NC} %-40s\n" "$name"
+        printf "%s" "$failures" | head -5
+    fi
+}
+
+test_sanitize() {
+    local name="$1" src="$2" want="$3"
+    local out="$OUTDIR/${name}.wasm" result output status
+    TOTAL=$((TOTAL + 1))
+    if ! result=$(compile "$src" "$out" "-scheduler=none -spmd-sanitize" 2>&1); then
+        COMPILE_FAIL=$((COMPILE_FAIL + 1))
+        printf "${RED}COMPILE FAIL${NC} %-40s %s\n" "$name" "$(echo "$result" | head -3)"
+    else
+        COMPILE_PASS=$((COMPILE_PASS + 1))
+        output=$(run_wasm "$out" 2>&1)
+        status=$?
+        sanitize_result "$name" "$(sanitize_check "$src" "$output" "$status" "$want")"
+    fi
+
+    [ "$(uname -m)" = "x86_64" ] || return 0
+    out="$OUTDIR/${name}_x86"
+    TOTAL=$((TOTAL + 1))
+    if ! result=$(compile_x86 "$src" "$out" "-spmd-sanitize" 2>&1); then
+        COMPILE_FAIL=$((COMPILE_FAIL + 1))
+        printf "${RED}COMPILE FAIL${NC} %-40s %s\n" "${name}_x86" "$(echo "$result" | tail -3)"
+        return 1
+    fi
+    COMPILE_PASS=$((COMPILE_PASS + 1))
+    output=$(timeout 10 "$out" 2>&1)
+    status=$?
+    sanitize_result "${name}_x86" "$(sanitize_check "$src" "$output" "$status" "$want")"
+}
+
+test_sanitize "sanitize_conflicts" "$SANITIZE_DIR/conflicts.go" 66
+test_sanitize "sanitize_bounds"    "$SANITIZE_DIR/bounds.go"    panic
+# addat.go uses lanes.AddAt, which is proposed together with histogram.
+proposed "sanitize_addat" "$INTEG/histogram/main.go" ||
+    test_sanitize "sanitize_addat" "$SANITIZE_DIR/addat.go" 0
+
+# Without the flag, the same stores run unchecked: no reports, status 0.
+TOTAL=$((TOTAL + 1))
+if ! result=$(compile "$SANITIZE_DIR/conflicts.go" "$OUTDIR/sanitize_plain.wasm" "-scheduler=none" 2>&1); then
+    COMPILE_FAIL=$((COMPILE_FAIL + 1))
+    printf "${RED}COMPILE FAIL${NC} %-40s %s\n" "sanitize_plain" "$(echo "$result" | head -3)"
+else
+    COMPILE_PASS=$((COMPILE_PASS + 1))
+    output=$(run_wasm "$OUTDIR/sanitize_plain.wasm" 2>&1)
+    status=$?
+    failures=""
+    [ "$status" = 0 ] || failures="want exit status 0, got $status"$'\n'
+    ! echo "$output" | grep -q "SPMD SANITIZER" || failures="${failures}unexpected report"$'\n'
+    echo "$output" | grep -qF "permute: [0 10 20 30 40 50 60 70]" || failures="${failures}missing permute output"$'\n'
+    sanitize_result "sanitize_plain" "$failures"
+fi
+
+fi  # -spmd-sanitize check
+
+# ========== LEVEL 16: Deterministic float reductions ==========
+# With tinygo -spmd-deterministic-fp, a float accumulator holds 16 partial
+# sums at every width, and reduce.Add and reduce.AddPrecise combine them in a
+# fixed tree (docs/superpowers/specs/2026-10-16-deterministic-float-reduce-design.md).
+# deterministic-fp must print its width-matrix golden output bit for bit in
+# the scalar fallback, at the WASM width under test, and natively with SSE
+# and AVX2. The level is skipped when tinygo does not know the flag.
+DETFP_SRC="$INTEG/deterministic-fp/main.go"
+DETFP_GOLDEN="$INTEG/testdata/width-matrix/deterministic-fp.golden"
+if tinygo_accepts_flag "-spmd-deterministic-fp"; then
+
+printf "\n${BLUE}--- Level 16: Deterministic float reductions (-spmd-deterministic-fp) ---${NC}\n"
+
+# test_detfp name build extra: build deterministic-fp with the build function
+# (compile, compile_x86 or compile_x86_avx2) and compare its output with the
+# golden file.
+test_detfp() {
+    local name="$1" build="$2" extra="${3:-}"
+    local out="$OUTDIR/${name}" result output
+    [ "$build" = compile ] && out="$out.wasm"
+    TOTAL=$((TOTAL + 1))
+    if ! result=$("$build" "$DETFP_SRC" "$out" "-scheduler=none -spmd-deterministic-fp $extra" 2>&1); then
+        COMPILE_FAIL=$((COMPILE_FAIL + 1))
+        printf "${RED}COMPILE FAIL${NC} %-40s %s\n" "$name" "$(echo "$result" | grep -v "^'+\|^$" | head -3)"
+        return 1
+    fi
+    COMPILE_PASS=$((COMPILE_PASS + 1))
+
+    if [ "$build" = compile ]; then
+        output=$(run_wasm "$out" 2>&1 | grep -v "ExperimentalWarning\|trace-warnings")
+    else
+        output=$(timeout 10 "$out" 2>&1)
+    fi
+    if [ "$output" = "$(cat "$DETFP_GOLDEN")" ]; then
+        RUN_PASS=$((RUN_PASS + 1))
+        printf "${GREEN}DETFP OK${NC}     %-40s\n" "$name"
+    else
+        RUN_FAIL=$((RUN_FAIL + 1))
+        printf "${RED}DETFP FAIL${NC}   %-40s\n" "$name"
+        diff <(echo "$output") "$DETFP_GOLDEN" | head -5
+    fi
+}
+
+test_detfp "detfp_scalar" compile "-simd=false"
+test_detfp "detfp_wasm"   compile
+if [ "$(uname -m)" = "x86_64" ]; then
+    test_detfp "detfp_x86"  compile_x86
+    test_detfp "detfp_avx2" compile_x86_avx2
+fi
+
+fi  # -spmd-deterministic-fp check
+
+# ========== LEVEL 17: SSA interpreter oracle ==========
+# x-tools-spmd's go/ssa/interp runs the SPMD SSA lane by lane
+# (docs/superpowers/specs/2026-10-16-spmd-ssa-interp-design.md). Its output is
+# the reference semantics: a diff against the WASM SIMD build is a miscompile
+# or a spec ambiguity, never a difference in lane count. Build it with
+# `make build-ssadump`; the level is skipped when the binary is missing.
+SSADUMP="${SSADUMP:-$SPMD_ROOT/x-tools-spmd/build/ssadump}"
+if [ -x "$SSADUMP" ]; then
+
+printf "\n${BLUE}--- Level 17: SSA interpreter oracle (ssadump -run) ---${NC}\n"
+
+INTERP_WIDTH="$SIMD_WIDTH"
+if [ "$INTERP_WIDTH" = "native" ]; then
+    INTERP_WIDTH=128  # the width of the WASM build it is compared with
+fi
+
+test_interp() {
+    local name="$1" src="$2"
+    proposed "$name" "$src" && return 0
+    local wasm_out="$OUTDIR/${name}.wasm"
+    TOTAL=$((TOTAL + 1))
+
+    if ! compile "$src" "$wasm_out" "-scheduler=none" >/dev/null 2>&1; then
+        COMPILE_FAIL=$((COMPILE_FAIL + 1))
+        printf "${RED}INTERP FAIL${NC}  %-40s %s\n" "$name" "WASM compile failed"
+        return 1
+    fi
+    local interp_output
+    if ! interp_output=$(GOEXPERIMENT=spmd GOROOT="$GOROOT_SPMD" \
+            "$SSADUMP" -run -simd-width="$INTERP_WIDTH" "$src" 2>&1); then
+        COMPILE_FAIL=$((COMPILE_FAIL + 1))
+        printf "${RED}INTERP FAIL${NC}  %-40s %s\n" "$name" "interpreter failed"
+        echo "$interp_output" | head -5
+        return 1
+    fi
+    COMPILE_PASS=$((COMPILE_PASS + 1))
+
+    # Same filtering as Level 8: runtime warnings and benchmark timings.
+    local wasm_output
+    wasm_output=$(run_wasm "$wasm_out" "" 2>&1 | grep -v "ExperimentalWarning\|trace-warnings\|^Scalar:\|^SPMD:\|^Speedup:")
+    interp_output=$(echo "$interp_output" | grep -v "^Scalar:\|^SPMD:\|^Speedup:")
+
+    if [ "$wasm_output" = "$interp_output" ]; then
+        RUN_PASS=$((RUN_PASS + 1))
+        printf "${GREEN}INTERP PASS${NC}  %-40s\n" "$name"
+    else
+        RUN_FAIL=$((RUN_FAIL + 1))
+        printf "${RED}INTERP FAIL${NC}  %-40s %s\n" "$name" "WASM output differs from interpreter"
+        diff <(echo "$interp_output") <(echo "$wasm_output") | head -5
+    fi
+}
+
+# Width-independent examples (the width matrix list, minus the two-package
+# spmd-export whose exported entry points the interpreter does not call).
+test_interp "interp_simple-sum"         "$INTEG/simple-sum/main.go"
+test_interp "interp_odd-even"           "$INTEG/odd-even/main.go"
+test_interp "interp_printf-verbs"       "$INTEG/printf-verbs/main.go"
+test_interp "interp_to-upper"           "$INTEG/to-upper/main.go"
+test_interp "interp_lo-sum"             "$INTEG/lo-sum/main.go"
+test_interp "interp_lo-mean"            "$INTEG/lo-mean/main.go"
+test_interp "interp_lo-min"             "$INTEG/lo-min/main.go"
+test_interp "interp_lo-max"             "$INTEG/lo-max/main.go"
+test_interp "interp_lo-contains"        "$INTEG/lo-contains/main.go"
+test_interp "interp_lo-clamp"           "$INTEG/lo-clamp/main.go"
+test_interp "interp_shifted-load"       "$INTEG/shifted-load/main.go"
+test_interp "interp_gather-coalesce"    "$INTEG/gather-coalesce/main.go"
+test_interp "interp_spmd-remarks"       "$INTEG/spmd-remarks/main.go"
+test_interp "interp_swizzle-rotate"     "$INTEG/swizzle-rotate/main.go"
+test_interp "interp_prefix-scan"        "$INTEG/prefix-scan/main.go"
+test_interp "interp_generic-spmd-calls" "$INTEG/generic-spmd-calls/main.go"
+test_interp "interp_ipv4-batch"         "$INTEG/ipv4-batch/main.go"
+test_interp "interp_varying-struct"     "$INTEG/varying-struct/main.go"
+test_interp "interp_reduce-semantics"   "$INTEG/reduce-semantics/main.go"
+test_interp "interp_debug-dwarf"        "$INTEG/debug-dwarf/main.go"
+test_interp "interp_varying-map-lookup" "$INTEG/varying-map-lookup/main.go"
+test_interp "interp_histogram"          "$INTEG/histogram/main.go"
+
+fi  # ssadump check
+
+# ========== LEVEL 18: Optimization remarks ==========
+# tinygo -spmd-remarks reports each SPMD lowering decision against its source
+# line (docs/superpowers/specs/2026-10-16-spmd-remarks-design.md). Every
+# "// REMARK" string in spmd-remarks/main.go must be reported on its line, and
+# the text and JSON formats must report the same remarks. The markers were
+# written for the native 128-bit WASM build; the level is skipped at other
+# widths and when tinygo does not know the flag.
+REMARKS_SRC="$INTEG/spmd-remarks/main.go"
+if [ -z "$SIMD_WIDTH_FLAG" ] && tinygo_accepts_flag "-spmd-remarks=text -spmd-remarks-output=/dev/null"; then
+
+printf "\n${BLUE}--- Level 18: Optimization remarks (-spmd-remarks) ---${NC}\n"
+
+# remarks format src out: build src with -spmd-remarks=format, writing the
+# remarks to out.
+remarks() {
+    local format="$1" src="$2" out="$3"
+    compile "$src" "$OUTDIR/remarks_${format}.wasm" \
+        "-scheduler=none -spmd-remarks=$format -spmd-remarks-output=$out"
+}
+
+test_remarks() {
+    local name="$1" src="$2"
+    local text_out="$OUTDIR/${name}.remarks.txt" json_out="$OUTDIR/${name}.remarks.jsonl"
+    TOTAL=$((TOTAL + 1))
+
+    local result
+    if ! result=$(remarks text "$src" "$text_out" 2>&1) || ! result=$(remarks json "$src" "$json_out" 2>&1); then
+        COMPILE_FAIL=$((COMPILE_FAIL + 1))
+        printf "${RED}REMARKS FAIL${NC} %-40s %s\n" "$name" "$(echo "$result" | head -3)"
+        return 1
+    fi
+    COMPILE_PASS=$((COMPILE_PASS + 1))
+
+    local file failures="" line want
+    file=$(basename "$src")
+    while IFS=: read -r line want; do
+        if ! grep -E "(^|/)$file:$line:[0-9]+: spmd: " "$text_out" | grep -qF "$want"; then
+            failures="${failures}missing $file:$line: $want"$'\n'
+        fi
+    done < <(grep -n '// REMARK "' "$src" | while IFS=: read -r line rest; do
+        echo "${rest#*// REMARK }" | grep -o '"[^"]*"' | tr -d '"' | sed "s/^/$line:/"
+    done)
+
+    local text_count json_count
+    text_count=$(grep -c ": spmd: " "$text_out")
+    json_count=$(grep -c '"kind":' "$json_out")
+    if [ "$text_count" != "$json_count" ]; then
+        failures="${failures}text has $text_count remarks, json has $json_count"$'\n'
+    fi
+
+    if [ -z "$failures" ]; then
+        RUN_PASS=$((RUN_PASS + 1))
+        printf "${GREEN}REMARKS OK${NC}   %-40s\n" "$name"
+    else
+        RUN_FAIL=$((RUN_FAIL + 1))
+        printf "${RED}REMARKS FAIL${NC} %-40s\n" "$name"
+        printf "%s" "$failures" | head -5
+    fi
+}
+
+test_remarks "remarks_spmd-remarks" "$REMARKS_SRC"
+
+fi  # -spmd-remarks check
+
 # ========== SUMMARY ==========
 echo ""
 printf "${BLUE}=== Summary ===${NC}\n"
@@ -860,6 +1778,7 @@ printf "${GREEN}Run pass:        %d${NC}\n" "$RUN_PASS"
 printf "${RED}Run fail:        %d${NC}\n" "$RUN_FAIL"
 printf "${GREEN}Reject pass:     %d${NC}\n" "$REJECT_PASS"
 printf "${RED}Reject fail:     %d${NC}\n" "$REJECT_FAIL"
+printf "${YELLOW}Skipped:         %d${NC}\n" "$SKIPPED"
 echo ""
 
 if [ "$COMPILE_FAIL" -gt 0 ] || [ "$RUN_FAIL" -gt 0 ] || [ "$REJECT_FAIL" -gt 0 ]; then
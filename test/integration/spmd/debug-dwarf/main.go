@@ -0,0 +1,76 @@
+// Per-lane debugging: DWARF for varying variables and the execution mask.
+// Each "// GDB" comment holds pairs of a gdb command and text that its output
+// must contain, at the first stop on that line. They are written for the
+// x86-64 SSE build (4 int32 lanes) with -opt=1; E2E Level 10b runs them with
+// tools/debug/spmd_gdb.py loaded.
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"reduce"
+)
+
+type point struct{ X, Y int32 }
+
+// sumBig adds twice each element above 25: a varying accumulator, a
+// varying bool and a partial mask.
+//
+//go:noinline
+func sumBig(data []int32) int32 {
+	var total lanes.Varying[int32]
+	go for _, v := range data {
+		doubled := v * 2 // GDB "print v" "= [10 20 30 40]" "print v[2]" "= 30"
+		big := v > 25
+		if big {
+			total += doubled // GDB "print big" "= [false false true true]" "print $spmd_mask()" "= [false false true true]" "info spmd" "doubled = [_ _ 60 80]"
+		}
+	}
+	return reduce.Add(total) // GDB "print total" "= [100 120 200 240]"
+}
+
+// farthest returns the largest squared distance from the origin: a varying
+// struct, stored as one vector per field.
+//
+//go:noinline
+func farthest(ps []point) int32 {
+	var best lanes.Varying[int32]
+	go for _, p := range ps {
+		d := p.X*p.X + p.Y*p.Y // GDB "print p" "= [{1 2} {3 4} {5 6} {7 8}]" "print p.Y[1]" "= 4"
+		if d > best {
+			best = d
+		}
+	}
+	return reduce.Max(best)
+}
+
+// clampNeg is an SPMD function: its mask comes from the caller.
+//
+//go:noinline
+func clampNeg(x lanes.Varying[int32]) lanes.Varying[int32] {
+	r := x
+	if x < 0 {
+		r = 0 // GDB "print $spmd_mask()" "= [false true false true]" "info spmd" "x = [_ -1 _ -1]"
+	}
+	return r
+}
+
+//go:noinline
+func sumClamped(src []int32) int32 {
+	var total lanes.Varying[int32]
+	go for _, v := range src {
+		total += clampNeg(v)
+	}
+	return reduce.Add(total)
+}
+
+func main() {
+	data := []int32{10, 20, 30, 40, 50, 60, 70, 80}
+	fmt.Println("sumBig:", sumBig(data))
+
+	ps := []point{{1, 2}, {3, 4}, {5, 6}, {7, 8}, {-2, 9}, {0, 0}, {4, -4}, {1, 1}}
+	fmt.Println("farthest:", farthest(ps))
+
+	src := []int32{3, -1, 4, -1, 5, -9, 2, -6}
+	fmt.Println("sumClamped:", sumClamped(src))
+}
@@ -0,0 +1,96 @@
+// run -goexperiment spmd -spmd-deterministic-fp
+//
+// Float sums built with -spmd-deterministic-fp. Each accumulator holds 16
+// partial sums whatever the lane count, and reduce.Add and reduce.AddPrecise
+// combine them in a fixed tree, so every line of output is bit-identical at
+// every SIMD width and in the scalar fallback. Without the flag, the sums
+// differ in the last bits from one width to the next.
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"math"
+	"reduce"
+)
+
+var scales = [...]float32{1e-3, 1e-2, 1e-1, 1, 1e1, 1e2, 1e3}
+
+// values returns n values of both signs with magnitudes from 1e-4 to 1e3,
+// so that the rounding of their sum depends on the order of the additions.
+// The count is not a multiple of 16, so every build runs a masked tail.
+func values(n int) []float32 {
+	xs := make([]float32, n)
+	seed := uint32(1)
+	for i := range xs {
+		seed = seed*1664525 + 1013904223
+		xs[i] = (float32(seed>>8)/(1<<24) - 0.5) * scales[seed%7]
+	}
+	return xs
+}
+
+// cancelling returns n values that repeat 1e8, 1, -1e8. The exact sum is
+// n/3, but a float32 sum drops each 1 next to 1e8.
+func cancelling(n int) []float32 {
+	xs := make([]float32, n)
+	for i := range xs {
+		xs[i] = [...]float32{1e8, 1, -1e8}[i%3]
+	}
+	return xs
+}
+
+func sum(xs []float32) float32 {
+	var acc lanes.Varying[float32]
+	go for _, x := range xs {
+		acc += x
+	}
+	return reduce.Add(acc)
+}
+
+// sumPositive accumulates under a varying if. A lane that is off leaves its
+// partial sum unchanged.
+func sumPositive(xs []float32) float32 {
+	var acc lanes.Varying[float32]
+	go for _, x := range xs {
+		if x > 0 {
+			acc += x
+		}
+	}
+	return reduce.Add(acc)
+}
+
+// sumPrecise keeps a compensation term next to each partial sum.
+func sumPrecise(xs []float32) float32 {
+	var acc lanes.Varying[float32]
+	go for _, x := range xs {
+		acc += x
+	}
+	return reduce.AddPrecise(acc)
+}
+
+// sum64 adds the same values as float64. There are 8 float64 lanes at
+// most, and still 16 partial sums.
+func sum64(xs []float32) float64 {
+	var acc lanes.Varying[float64]
+	go for _, x := range xs {
+		acc += float64(x)
+	}
+	return reduce.Add(acc)
+}
+
+func print32(name string, s float32) {
+	fmt.Printf("%s: %.9g (%08x)\n", name, s, math.Float32bits(s))
+}
+
+func main() {
+	xs := values(1000)
+	print32("sum", sum(xs))
+	print32("sumPositive", sumPositive(xs))
+	print32("sumPrecise", sumPrecise(xs))
+	s := sum64(xs)
+	fmt.Printf("sum64: %.17g (%016x)\n", s, math.Float64bits(s))
+
+	ys := cancelling(300)
+	print32("cancelling sum", sum(ys))
+	print32("cancelling sumPrecise", sumPrecise(ys))
+}
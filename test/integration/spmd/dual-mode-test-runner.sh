@@ -191,6 +191,11 @@ test_illegal_examples() {
         "varying-to-uniform.go"
     )
     
+    # Proposed: rejected only once their feature lands, may compile until then
+    local proposed_illegal_examples=(
+        "spmd-export-misuse.go"
+    )
+    
     for illegal_file in "${illegal_examples[@]}"; do
         local full_path="$illegal_dir/$illegal_file"
         if [ -f "$full_path" ]; then
@@ -202,6 +207,18 @@ test_illegal_examples() {
             rm -f illegal_test.wasm
         fi
     done
+    
+    for illegal_file in "${proposed_illegal_examples[@]}"; do
+        local full_path="$illegal_dir/$illegal_file"
+        if [ -f "$full_path" ]; then
+            if tinygo build -target=wasi -o "illegal_test.wasm" "$full_path" 2>/dev/null; then
+                log_warning "$illegal_file compiled (proposed, not yet implemented)"
+            else
+                log_success "$illegal_file correctly failed compilation"
+            fi
+            rm -f illegal_test.wasm
+        fi
+    done
 }
 
 # Test legacy compatibility (without GOEXPERIMENT=spmd)
@@ -289,6 +306,15 @@ main() {
         "union-type-generics"
         "type-casting-varying"
         "varying-array-iteration"
+        "lo-sum"
+        "lo-mean"
+        "lo-min"
+        "lo-max"
+        "lo-contains"
+        "lo-clamp"
+        "shifted-load"
+        "gather-coalesce"
+        "spmd-remarks"
         "mandelbrot"
     )
     
@@ -298,6 +324,25 @@ main() {
         "ipv4-parser"
     )
     
+    # Proposed examples: specified in docs/superpowers/specs, not yet
+    # implemented in the go and tinygo forks
+    local proposed_examples=(
+        "swizzle-rotate"
+        "prefix-scan"
+        "generic-spmd-calls"
+        "spmd-export"
+        "ipv4-batch"
+        "varying-struct"
+        "reduce-semantics"
+        "debug-dwarf"
+        "varying-map-lookup"
+        "histogram"
+        # deterministic-fp is not run here: its SIMD and scalar sums only
+        # match with -spmd-deterministic-fp, which this runner does not pass.
+        # Level 16 of test/e2e/spmd-e2e-test.sh and the width matrix build it
+        # with the flag.
+    )
+    
     echo -e "${BLUE}=== Testing Basic SPMD Examples ===${NC}"
     for example in "${spmd_examples[@]}"; do
         if [ -d "$example" ] && [ -f "$example/main.go" ]; then
@@ -327,6 +372,25 @@ main() {
         rm -f "${example}-simd.wasm" "${example}-scalar.wasm"
     done
     
+    echo -e "${BLUE}=== Testing Proposed Examples (May Fail) ===${NC}"
+    for example in "${proposed_examples[@]}"; do
+        if [ -d "$example" ] && [ -f "$example/main.go" ]; then
+            # Probe quietly first: a failed build here is expected and must
+            # not count as a test failure
+            if GOEXPERIMENT=spmd tinygo build -target=wasi -o "${example}-simd.wasm" "$example/main.go" 2>/dev/null; then
+                log_warning "$example unexpectedly compiled (feature landed, move it to spmd_examples)"
+                if test_dual_mode_compilation "$example" "$example"; then
+                    test_runtime_execution "$example"
+                fi
+            else
+                log_warning "$example failed as expected (specified, not yet implemented)"
+            fi
+            echo
+        fi
+        # Clean up WASM files
+        rm -f "${example}-simd.wasm" "${example}-scalar.wasm"
+    done
+    
     echo -e "${BLUE}=== Testing Error Conditions ===${NC}"
     test_illegal_examples
     echo
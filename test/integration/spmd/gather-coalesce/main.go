@@ -0,0 +1,164 @@
+// run -goexperiment spmd
+//
+// Gather coalescing: loads from one table whose indices differ by small
+// constants (tbl[3*i], tbl[3*i+1], tbl[3*i+2]) are to be grouped into one
+// SPMDGatherGroup. A strided group becomes a few contiguous vector loads and
+// one shuffle per member; a group on a small in-register table becomes one
+// swizzle. Until the pass lands this example checks output only: it passes
+// with one gather per load too. Every table is sliced to the highest index
+// read, so a group load that ignores the tail mask or the gaps between
+// members reads past the end.
+package main
+
+import "fmt"
+
+// gray converts packed RGB pixels: stride 3, offsets 0, 1, 2.
+func gray(pix []byte, out []byte) {
+	go for i := range out {
+		r := int32(pix[3*i])
+		g := int32(pix[3*i+1])
+		b := int32(pix[3*i+2])
+		out[i] = byte((77*r + 150*g + 29*b) >> 8)
+	}
+}
+
+// grayRGBA skips the alpha channel: stride 4, offsets 0, 1, 2. The last
+// pixel has no alpha byte, so offset 3 must never be loaded.
+func grayRGBA(pix []byte, out []byte) {
+	go for i := range out {
+		r := int32(pix[4*i])
+		g := int32(pix[4*i+1])
+		b := int32(pix[4*i+2])
+		out[i] = byte((77*r + 150*g + 29*b) >> 8)
+	}
+}
+
+// cross multiplies interleaved int32 pairs: stride 2, two members.
+func cross(xy []int32, out []int32) {
+	go for i := range out {
+		out[i] = xy[2*i]*xy[2*i+1] - xy[2*i+1]
+	}
+}
+
+// row reads one row of a packed RGB image: a uniform base plus a stride.
+func row(img []byte, width, y int, out []int32) {
+	go for x := range out {
+		p := y*width*3 + 3*x
+		out[x] = int32(img[p]) + int32(img[p+1]) + int32(img[p+2])
+	}
+}
+
+// pick reads different members under different masks. The group load
+// covers the union of the active lanes; each member keeps its own mask.
+func pick(v []int32, out []int32) {
+	go for i := range out {
+		if i%2 == 0 {
+			out[i] = v[3*i]
+		} else {
+			out[i] = v[3*i+1] + v[3*i+2]
+		}
+	}
+}
+
+// fields parses three-digit fields out of a 16-byte register. The base is a
+// varying start offset, so the group is one swizzle of the digit table.
+func fields(digits *[16]byte, starts []int32, out []int32) {
+	go for i, s := range starts {
+		d2 := int32(digits[s])
+		d1 := int32(digits[s+1])
+		d0 := int32(digits[s+2])
+		out[i] = d2*100 + d1*10 + d0
+	}
+}
+
+func checkBytes(name string, got []byte, want func(i int) byte) bool {
+	for i := range got {
+		if w := want(i); got[i] != w {
+			fmt.Printf("%s[%d]: got %d, want %d\n", name, i, got[i], w)
+			return false
+		}
+	}
+	return true
+}
+
+func checkInt32(name string, got []int32, want func(i int) int32) bool {
+	for i := range got {
+		if w := want(i); got[i] != w {
+			fmt.Printf("%s[%d]: got %d, want %d\n", name, i, got[i], w)
+			return false
+		}
+	}
+	return true
+}
+
+func luma(r, g, b byte) byte {
+	return byte((77*int32(r) + 150*int32(g) + 29*int32(b)) >> 8)
+}
+
+func main() {
+	const n = 37 // not a multiple of any lane count: exercises the tail mask
+	pix := make([]byte, 6*n)
+	for i := range pix {
+		pix[i] = byte(i*53 + 17)
+	}
+	ints := make([]int32, 3*n)
+	for i := range ints {
+		ints[i] = int32(i*i%97 - 40)
+	}
+
+	ok := true
+
+	out := make([]byte, n)
+	gray(pix[:3*n], out)
+	ok = checkBytes("gray", out, func(i int) byte { return luma(pix[3*i], pix[3*i+1], pix[3*i+2]) }) && ok
+	fmt.Printf("gray[0:6]: %v\n", out[:6])
+
+	grayRGBA(pix[:4*n-1], out)
+	ok = checkBytes("grayRGBA", out, func(i int) byte { return luma(pix[4*i], pix[4*i+1], pix[4*i+2]) }) && ok
+	fmt.Printf("grayRGBA[0:6]: %v\n", out[:6])
+
+	res := make([]int32, n)
+	cross(ints[:2*n], res)
+	ok = checkInt32("cross", res, func(i int) int32 { return ints[2*i]*ints[2*i+1] - ints[2*i+1] }) && ok
+	fmt.Printf("cross[0:6]: %v\n", res[:6])
+
+	const width = n
+	img := pix[:2*3*width] // two rows; row 1 ends at the end of the slice
+	row(img, width, 1, res)
+	ok = checkInt32("row", res, func(x int) int32 {
+		p := width*3 + 3*x
+		return int32(img[p]) + int32(img[p+1]) + int32(img[p+2])
+	}) && ok
+	fmt.Printf("row[0:6]: %v\n", res[:6])
+
+	pick(ints[:3*n], res)
+	ok = checkInt32("pick", res, func(i int) int32 {
+		if i%2 == 0 {
+			return ints[3*i]
+		}
+		return ints[3*i+1] + ints[3*i+2]
+	}) && ok
+	fmt.Printf("pick[0:6]: %v\n", res[:6])
+
+	var digits [16]byte
+	copy(digits[:], "1921680012554390")
+	for i := range digits {
+		digits[i] -= '0'
+	}
+	starts := make([]int32, n)
+	for i := range starts {
+		starts[i] = int32(i * 5 % 14)
+	}
+	fields(&digits, starts, res)
+	ok = checkInt32("fields", res, func(i int) int32 {
+		s := starts[i]
+		return int32(digits[s])*100 + int32(digits[s+1])*10 + int32(digits[s+2])
+	}) && ok
+	fmt.Printf("fields[0:6]: %v\n", res[:6])
+
+	if ok {
+		fmt.Println("Correctness: PASS")
+	} else {
+		fmt.Println("Correctness: FAIL")
+	}
+}
@@ -0,0 +1,111 @@
+// run -goexperiment spmd
+//
+// Generic SPMD functions calling other generic SPMD functions. The helpers are
+// layered the way a library would write them: clampV calls minV and maxV,
+// sumClamped wraps a go for loop around clampV, and rangeSum instantiates
+// sumClamped for every element type. Each layer is instantiated from inside
+// another generic body, so the varying type parameters and the execution mask
+// have to be threaded through every level.
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"reduce"
+)
+
+type number interface {
+	~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+func minV[T number](a, b lanes.Varying[T]) lanes.Varying[T] {
+	if b < a {
+		return b
+	}
+	return a
+}
+
+func maxV[T number](a, b lanes.Varying[T]) lanes.Varying[T] {
+	if b > a {
+		return b
+	}
+	return a
+}
+
+// clampV is two generic calls deep.
+func clampV[T number](v lanes.Varying[T], lo, hi T) lanes.Varying[T] {
+	return minV(maxV(v, lo), hi)
+}
+
+// clampOdd only clamps odd positions, so clampV runs under a partial mask.
+func clampOdd[T number](v lanes.Varying[T], i lanes.Varying[int], lo, hi T) lanes.Varying[T] {
+	if i%2 == 1 {
+		v = clampV(v, lo, hi)
+	}
+	return v
+}
+
+// sumClamped is a generic go for loop whose body calls the generic helpers.
+func sumClamped[T number](data []T, lo, hi T) T {
+	var total lanes.Varying[T]
+	go for i, v := range data {
+		total += clampOdd(v, i, lo, hi)
+	}
+	return reduce.Add(total)
+}
+
+// sumClampedScalar is the reference implementation.
+func sumClampedScalar[T number](data []T, lo, hi T) T {
+	var total T
+	for i, v := range data {
+		if i%2 == 1 {
+			v = min(max(v, lo), hi)
+		}
+		total += v
+	}
+	return total
+}
+
+// check instantiates the whole chain for one element type.
+func check[T number](name string, data []T, lo, hi T) bool {
+	got := sumClamped(data, lo, hi)
+	want := sumClampedScalar(data, lo, hi)
+	ok := got == want
+	status := "PASS"
+	if !ok {
+		status = "FAIL"
+	}
+	fmt.Printf("%-8s sum=%v want=%v %s\n", name, got, want, status)
+	return ok
+}
+
+func makeData[T number](n int, f func(i int) T) []T {
+	data := make([]T, n)
+	for i := range data {
+		data[i] = f(i)
+	}
+	return data
+}
+
+func main() {
+	const n = 37 // leaves a partial tail at every lane count
+
+	ok := true
+	ok = check("int8", makeData(n, func(i int) int8 { return int8(i%11 - 5) }), -2, 3) && ok
+	ok = check("int16", makeData(n, func(i int) int16 { return int16(i*37 - 600) }), -100, 400) && ok
+	ok = check("int32", makeData(n, func(i int) int32 { return int32(i*1000 - 18000) }), -5000, 5000) && ok
+	ok = check("int64", makeData(n, func(i int) int64 { return int64(i)<<33 - 1<<37 }), -1<<35, 1<<36) && ok
+	ok = check("uint8", makeData(n, func(i int) uint8 { return uint8(i * 7) }), 20, 200) && ok
+	ok = check("uint16", makeData(n, func(i int) uint16 { return uint16(i * 1500) }), 1000, 40000) && ok
+	ok = check("uint32", makeData(n, func(i int) uint32 { return uint32(i) * 100000 }), 500000, 2500000) && ok
+	ok = check("uint64", makeData(n, func(i int) uint64 { return uint64(i) << 40 }), 1<<41, 1<<44) && ok
+	// Float inputs are small integers, so the sum is exact in any order.
+	ok = check("float32", makeData(n, func(i int) float32 { return float32(i - 18) }), -4, 9) && ok
+	ok = check("float64", makeData(n, func(i int) float64 { return float64(i*3 - 50) }), -20, 35) && ok
+
+	if ok {
+		fmt.Println("Correctness: PASS")
+	} else {
+		fmt.Println("Correctness: FAIL")
+	}
+}
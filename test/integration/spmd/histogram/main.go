@@ -0,0 +1,128 @@
+// run -goexperiment spmd
+//
+// Histograms with lanes.AddAt and lanes.AtomicAdd. Lanes that update the same
+// element in one iteration are combined before the store, so no update is
+// lost: the result is the one a scalar loop gives. The input is chosen so
+// that keys repeat within every group of lanes, at every lane count. Float
+// weights are multiples of 1/4, so their sums are exact in any order, and
+// every result is checked against a scalar loop.
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"os"
+)
+
+type Sample struct {
+	Value  int32
+	Weight float32
+}
+
+// byteHistogram counts each byte of data.
+func byteHistogram(data []byte, counts []int32) {
+	go for _, b := range data {
+		lanes.AddAt(counts, b, 1)
+	}
+}
+
+// weighted adds each sample's weight to the bucket of its value.
+func weighted(samples []Sample, totals []float32) {
+	go for _, s := range samples {
+		lanes.AddAt(totals, s.Value/10, s.Weight)
+	}
+}
+
+// positives sums the positive values by value % 4. Negative values are off in
+// the mask: their index would be out of range, and it is not checked.
+func positives(values []int32, sums []int32) {
+	go for _, v := range values {
+		if v > 0 {
+			lanes.AddAt(sums, v%4, v)
+		}
+	}
+}
+
+// tickets numbers the items of each queue in order: AtomicAdd returns each
+// lane's new value, as if the lanes had added one at a time in lane order.
+func tickets(queues []int32, next []int32, out []int32) {
+	go for i, q := range queues {
+		out[i] = lanes.AtomicAdd(&next[q], 1)
+	}
+}
+
+func main() {
+	ok := true
+	check := func(name string, got, want any) {
+		fmt.Printf("%s: %v\n", name, got)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			fmt.Printf("  want %v\n", want)
+			ok = false
+		}
+	}
+
+	text := []byte("mississippi banana bandana; abracadabra")
+	counts := make([]int32, 128)
+	byteHistogram(text, counts)
+	wantCounts := make([]int32, 128)
+	for _, b := range text {
+		wantCounts[b]++
+	}
+	check("counts[abdinps]", pick(counts, "abdinps"), pick(wantCounts, "abdinps"))
+	if fmt.Sprint(counts) != fmt.Sprint(wantCounts) {
+		fmt.Println("  byteHistogram: other bytes differ")
+		ok = false
+	}
+
+	// 19 samples: not a multiple of any lane count, so every build has a tail.
+	samples := make([]Sample, 19)
+	for i := range samples {
+		samples[i] = Sample{Value: int32(i*i) % 40, Weight: float32(i%5+1) * 0.25}
+	}
+	totals := make([]float32, 4)
+	weighted(samples, totals)
+	wantTotals := make([]float32, 4)
+	for _, s := range samples {
+		wantTotals[s.Value/10] += s.Weight
+	}
+	check("weighted", totals, wantTotals)
+
+	values := []int32{5, -3, 9, 13, -8, 2, 6, -1, 17, 4, 8, 21, -6}
+	sums := make([]int32, 4)
+	positives(values, sums)
+	wantSums := make([]int32, 4)
+	for _, v := range values {
+		if v > 0 {
+			wantSums[v%4] += v
+		}
+	}
+	check("positives", sums, wantSums)
+
+	queues := []int32{0, 2, 0, 0, 1, 2, 2, 0, 1, 0, 2}
+	next := make([]int32, 3)
+	out := make([]int32, len(queues))
+	tickets(queues, next, out)
+	wantNext := make([]int32, 3)
+	wantOut := make([]int32, len(queues))
+	for i, q := range queues {
+		wantNext[q]++
+		wantOut[i] = wantNext[q]
+	}
+	check("tickets", out, wantOut)
+	check("next", next, wantNext)
+
+	if !ok {
+		fmt.Println("Correctness: FAIL")
+		os.Exit(1)
+	}
+	fmt.Println("Correctness: PASS")
+}
+
+// pick returns the counts of the given bytes.
+func pick(counts []int32, keys string) []int32 {
+	out := make([]int32, len(keys))
+	for i := range keys {
+		out[i] = counts[keys[i]]
+	}
+	return out
+}
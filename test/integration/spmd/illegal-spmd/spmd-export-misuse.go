@@ -0,0 +1,39 @@
+// errorcheck -goexperiment spmd
+
+package main
+
+import "lanes"
+
+type Vec struct{}
+
+// ILLEGAL: the directive only applies to exported functions
+//
+//go:spmd export
+func double(v lanes.Varying[int]) lanes.Varying[int] { // ERROR "//go:spmd export on unexported function"
+	return v * 2
+}
+
+// ILLEGAL: methods cannot use the exported SPMD ABI
+//
+//go:spmd export
+func (Vec) Double(v lanes.Varying[int]) lanes.Varying[int] { // ERROR "//go:spmd export not allowed on methods"
+	return v * 2
+}
+
+// ILLEGAL: the directive does not make a function SPMD on its own
+//
+//go:spmd export
+func Sum(data []int) int { // ERROR "//go:spmd export on function without varying parameters or results"
+	return len(data)
+}
+
+// LEGAL: exported function with the directive
+//
+//go:spmd export
+func Double(v lanes.Varying[int]) lanes.Varying[int] {
+	return v * 2
+}
+
+func main() {
+	println(Sum(nil))
+}
@@ -47,6 +47,15 @@ var (
 		"union-type-generics",
 		"type-casting-varying",
 		"varying-array-iteration",
+		"lo-sum",
+		"lo-mean",
+		"lo-min",
+		"lo-max",
+		"lo-contains",
+		"lo-clamp",
+		"shifted-load",
+		"gather-coalesce",
+		"spmd-remarks",
 		"mandelbrot",
 	}
 	
@@ -55,6 +64,29 @@ var (
 		"ipv4-parser",
 	}
 	
+	// Proposed examples exercise features that are specified in
+	// docs/superpowers/specs but not yet implemented in the go and tinygo
+	// forks. Move an example to basicExamples when its feature lands.
+	proposedExamples = []string{
+		"swizzle-rotate",
+		"prefix-scan",
+		"generic-spmd-calls",
+		"spmd-export",
+		"ipv4-batch",
+		"varying-struct",
+		"reduce-semantics",
+		"debug-dwarf",
+		"deterministic-fp",
+		"varying-map-lookup",
+		"histogram",
+	}
+	
+	// Proposed illegal examples are rejected only once their feature lands;
+	// until then they may compile.
+	proposedIllegalExamples = []string{
+		"spmd-export-misuse.go",
+	}
+	
 	illegalExamples = []string{
 		"break-in-go-for.go",
 		"control-flow-outside-spmd.go", 
@@ -234,6 +266,35 @@ func TestSPMDAdvancedExamplesMayFail(t *testing.T) {
 	}
 }
 
+func TestSPMDProposedExamplesMayFail(t *testing.T) {
+	checkTinyGo(t)
+	
+	for _, example := range proposedExamples {
+		example := example // capture loop variable
+		t.Run(example, func(t *testing.T) {
+			t.Parallel()
+			
+			// Check if example directory exists
+			exampleDir := example
+			if _, err := os.Stat(exampleDir); os.IsNotExist(err) {
+				t.Skipf("Example %s not found", example)
+			}
+			
+			t.Logf("Testing proposed example %s (may fail)", example)
+			
+			// The feature is specified but not implemented yet
+			simdWasm, err := buildSPMDExample(t, example, true)
+			if err != nil {
+				t.Logf("Proposed example %s failed as expected: %v", example, err)
+				return
+			}
+			defer os.Remove(simdWasm)
+			
+			t.Logf("Proposed example %s unexpectedly succeeded", example)
+		})
+	}
+}
+
 func TestSPMDIllegalExamplesFailCompilation(t *testing.T) {
 	checkTinyGo(t)
 	
@@ -276,6 +337,48 @@ func TestSPMDIllegalExamplesFailCompilation(t *testing.T) {
 	}
 }
 
+func TestSPMDProposedIllegalExamplesMayCompile(t *testing.T) {
+	checkTinyGo(t)
+	
+	// Set GOEXPERIMENT=spmd
+	env := os.Environ()
+	env = append(env, "GOEXPERIMENT=spmd")
+	
+	illegalDir := "illegal-spmd"
+	if _, err := os.Stat(illegalDir); os.IsNotExist(err) {
+		t.Skip("Illegal examples directory not found")
+	}
+	
+	for _, illegalFile := range proposedIllegalExamples {
+		illegalFile := illegalFile // capture loop variable
+		t.Run(illegalFile, func(t *testing.T) {
+			t.Parallel()
+			
+			fullPath := filepath.Join(illegalDir, illegalFile)
+			if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+				t.Skipf("Illegal example %s not found", illegalFile)
+			}
+			
+			outputWasm := fmt.Sprintf("illegal-%s.wasm", strings.TrimSuffix(illegalFile, ".go"))
+			
+			cmd := exec.Command(tinygoPath, "build", "-target=wasi", "-o", outputWasm, fullPath)
+			cmd.Env = env
+			
+			output, err := cmd.CombinedOutput()
+			
+			// Clean up any accidentally created WASM file
+			os.Remove(outputWasm)
+			
+			if err == nil {
+				t.Logf("Proposed illegal example %s compiled (feature not implemented yet)", illegalFile)
+			} else {
+				t.Logf("Proposed illegal example %s correctly failed compilation: %v", illegalFile, err)
+				t.Logf("Compilation output: %s", output)
+			}
+		})
+	}
+}
+
 func TestSPMDLegacyCompatibility(t *testing.T) {
 	checkTinyGo(t)
 	
@@ -390,7 +493,9 @@ func TestSPMDTestInfrastructure(t *testing.T) {
 	t.Log("Test categories:")
 	t.Logf("  - Basic examples: %d", len(basicExamples))
 	t.Logf("  - Advanced examples: %d", len(advancedExamples))
+	t.Logf("  - Proposed examples: %d", len(proposedExamples))
 	t.Logf("  - Illegal examples: %d", len(illegalExamples))
+	t.Logf("  - Proposed illegal examples: %d", len(proposedIllegalExamples))
 	t.Logf("  - Legacy examples: %d", len(legacyExamples))
 	
 	// Verify test runner script exists
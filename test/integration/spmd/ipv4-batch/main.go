@@ -0,0 +1,146 @@
+// run -goexperiment spmd
+//
+// Outer-loop IPv4 parsing: one address per lane. Unlike ipv4-parser, which
+// spreads the 4 octets of a single address over the lanes, each lane here walks
+// its own string. The inner byte loop is divergent (strings have different
+// lengths), every s[j] is a per-lane byte gather, and each lane stops at its
+// own first error.
+package main
+
+import (
+	"fmt"
+	"lanes"
+)
+
+const (
+	errNone int32 = iota
+	errChar
+	errRange
+	errLeadingZero
+	errFields
+)
+
+var errNames = [...]string{"ok", "unexpected character", "field > 255", "leading zero", "wrong field count"}
+
+// parseBatch parses inputs[i] into addrs[i], with the error code in errs[i].
+func parseBatch(inputs []string, addrs []uint32, errs []int32) {
+	go for i, s := range inputs {
+		var addr, val lanes.Varying[uint32]
+		var fields, digits, code lanes.Varying[int32]
+
+		for j := 0; j < len(s); j++ { // j is uniform; len(s) is per lane
+			c := s[j]
+			if c == '.' {
+				if digits == 0 || fields == 3 {
+					code = errFields
+					break
+				}
+				addr = addr<<8 | val
+				fields++
+				val, digits = 0, 0
+			} else if c >= '0' && c <= '9' {
+				if digits == 1 && val == 0 {
+					code = errLeadingZero
+					break
+				}
+				val = val*10 + lanes.Varying[uint32](c-'0')
+				digits++
+				if val > 255 {
+					code = errRange
+					break
+				}
+			} else {
+				code = errChar
+				break
+			}
+		}
+
+		if code == errNone && (fields != 3 || digits == 0) {
+			code = errFields
+		}
+		if code == errNone {
+			addr = addr<<8 | val
+		} else {
+			addr = 0
+		}
+		addrs[i] = addr
+		errs[i] = code
+	}
+}
+
+// parseScalar is the reference implementation of the same state machine.
+func parseScalar(s string) (uint32, int32) {
+	var addr, val uint32
+	var fields, digits int32
+	for j := 0; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '.':
+			if digits == 0 || fields == 3 {
+				return 0, errFields
+			}
+			addr = addr<<8 | val
+			fields++
+			val, digits = 0, 0
+		case c >= '0' && c <= '9':
+			if digits == 1 && val == 0 {
+				return 0, errLeadingZero
+			}
+			val = val*10 + uint32(c-'0')
+			digits++
+			if val > 255 {
+				return 0, errRange
+			}
+		default:
+			return 0, errChar
+		}
+	}
+	if fields != 3 || digits == 0 {
+		return 0, errFields
+	}
+	return addr<<8 | val, errNone
+}
+
+func formatAddr(a uint32) string {
+	return fmt.Sprintf("%d.%d.%d.%d", a>>24, a>>16&0xff, a>>8&0xff, a&0xff)
+}
+
+func main() {
+	inputs := []string{
+		"192.168.1.1", "127.0.0.1", "192.168.1.a", "256.1.1.1", "192.168.01.1",
+		"0.0.0.0", "255.255.255.255", "10.0.0", "1.2.3.4.5", "8.8.8.8",
+		"", "1..2.3", "172.16.254.1", "100.64.0.1", "1.2.3.",
+		"203.0.113.7", "198.51.100.23", "9.9.9.9", "1.1.1.1000", "224.0.0.251",
+	}
+	// Grow to 37 records so the last group of lanes is partial at every width.
+	for i := 0; len(inputs) < 37; i++ {
+		inputs = append(inputs, fmt.Sprintf("%d.%d.%d.%d", i*37%256, i*11%256, i, 255-i))
+	}
+
+	addrs := make([]uint32, len(inputs))
+	errs := make([]int32, len(inputs))
+	parseBatch(inputs, addrs, errs)
+
+	ok := true
+	for i, s := range inputs {
+		wantAddr, wantErr := parseScalar(s)
+		if addrs[i] != wantAddr || errs[i] != wantErr {
+			fmt.Printf("MISMATCH %q: got %s/%s, want %s/%s\n", s,
+				formatAddr(addrs[i]), errNames[errs[i]], formatAddr(wantAddr), errNames[wantErr])
+			ok = false
+		}
+	}
+	for i := range 10 {
+		if errs[i] == errNone {
+			fmt.Printf("'%s' -> %s\n", inputs[i], formatAddr(addrs[i]))
+		} else {
+			fmt.Printf("'%s' -> ERROR: %s\n", inputs[i], errNames[errs[i]])
+		}
+	}
+
+	if ok {
+		fmt.Println("Correctness: PASS")
+	} else {
+		fmt.Println("Correctness: FAIL")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"lanes"
+)
+
+// Prefix-scan builtins: stream compaction, running maximum and bucket offsets.
+// Each scan carries its last value into the next loop iteration, so results
+// do not depend on the lane count.
+
+// compactEven writes the even values of data to out, preserving order, and
+// returns how many were written. The exclusive scan of the keep flags gives
+// each kept lane its output slot.
+func compactEven(data []int32, out []int32) int {
+	var written int32
+	go for _, v := range data {
+		var keep lanes.Varying[int32]
+		if v%2 == 0 {
+			keep = 1
+		}
+		var pos lanes.Varying[int32]
+		pos, written = lanes.ExclusiveScanAdd(keep, written)
+		if keep == 1 {
+			out[pos] = v
+		}
+	}
+	return int(written)
+}
+
+// runningMax stores max(data[0..i]) into out[i].
+func runningMax(data []int32, out []int32) {
+	var best int32 = -1 << 31
+	go for i, v := range data {
+		out[i], best = lanes.ScanMax(v, best)
+	}
+}
+
+// runningOr stores data[0] | ... | data[i] into out[i].
+func runningOr(data []uint16, out []uint16) {
+	var acc uint16
+	go for i, v := range data {
+		out[i], acc = lanes.ScanOr(v, acc)
+	}
+}
+
+// bucketOffsets turns per-bucket counts into start offsets (exclusive prefix sum).
+func bucketOffsets(counts []int64, offsets []int64) int64 {
+	var total int64
+	go for i, c := range counts {
+		offsets[i], total = lanes.ExclusiveScanAdd(c, total)
+	}
+	return total
+}
+
+func main() {
+	const n = 37 // not a multiple of any lane count: exercises the tail mask
+	data := make([]int32, n)
+	bits := make([]uint16, n)
+	counts := make([]int64, n)
+	for i := range n {
+		data[i] = int32((i*29 + 7) % 50)
+		bits[i] = uint16(1) << (i % 16)
+		counts[i] = int64(i % 5)
+	}
+
+	ok := true
+
+	// Stream compaction.
+	out := make([]int32, n)
+	got := compactEven(data, out)
+	var want []int32
+	for _, v := range data {
+		if v%2 == 0 {
+			want = append(want, v)
+		}
+	}
+	compactOK := got == len(want)
+	for i := 0; compactOK && i < got; i++ {
+		compactOK = out[i] == want[i]
+	}
+	fmt.Printf("Compacted %d even values\n", got)
+	ok = ok && compactOK
+
+	// Running maximum.
+	maxOut := make([]int32, n)
+	runningMax(data, maxOut)
+	best := int32(-1 << 31)
+	maxOK := true
+	for i, v := range data {
+		best = max(best, v)
+		maxOK = maxOK && maxOut[i] == best
+	}
+	fmt.Printf("Running max final: %d\n", maxOut[n-1])
+	ok = ok && maxOK
+
+	// Running OR.
+	orOut := make([]uint16, n)
+	runningOr(bits, orOut)
+	var acc uint16
+	orOK := true
+	for i, v := range bits {
+		acc |= v
+		orOK = orOK && orOut[i] == acc
+	}
+	fmt.Printf("Running or final: %#x\n", orOut[n-1])
+	ok = ok && orOK
+
+	// Bucket offsets.
+	offsets := make([]int64, n)
+	total := bucketOffsets(counts, offsets)
+	var sum int64
+	offOK := true
+	for i, c := range counts {
+		offOK = offOK && offsets[i] == sum
+		sum += c
+	}
+	offOK = offOK && total == sum
+	fmt.Printf("Bucket total: %d\n", total)
+	ok = ok && offOK
+
+	if ok {
+		fmt.Println("Correctness: PASS")
+	} else {
+		fmt.Println("Correctness: FAIL")
+	}
+}
@@ -0,0 +1,185 @@
+// run -goexperiment spmd
+//
+// Every reduce function, inside and outside a varying if. Each kernel folds
+// one reduction per loop iteration into a uniform total, so the output does
+// not depend on the lane count and must match the pure-Go scalar semantics
+// of the reduce package (one lane per iteration in the gc scalar fallback).
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"math/bits"
+	"reduce"
+)
+
+type results struct {
+	Sum, Prod       int64
+	Min, Max        int32
+	Or, And, Xor    uint32
+	FSum            float32
+	All, Any        bool
+	Count, MaskBits int
+	First           int
+	MaskedSum       int64
+	MaskedMin       int32
+	MaskedCount     int
+	MaskedEvens     int
+}
+
+func arith(data []int32, r *results) {
+	var sum int64
+	var prod int64 = 1
+	var lo, hi int32 = 1<<31 - 1, -1 << 31
+	go for _, x := range data {
+		sum += int64(reduce.Add(x))
+		prod *= int64(reduce.Mul(x&1 + 1))
+		lo = min(lo, reduce.Min(x))
+		hi = max(hi, reduce.Max(x))
+	}
+	r.Sum, r.Prod, r.Min, r.Max = sum, prod, lo, hi
+}
+
+func bitwise(data []uint32, r *results) {
+	var or, xor uint32
+	and := ^uint32(0)
+	go for _, x := range data {
+		or |= reduce.Or(x)
+		and &= reduce.And(x | 0x100)
+		xor ^= reduce.Xor(x)
+	}
+	r.Or, r.And, r.Xor = or, and, xor
+}
+
+func floats(data []float32, r *results) {
+	var sum float32
+	go for _, x := range data {
+		sum += reduce.Add(x) // multiples of 0.25: exact in any order
+	}
+	r.FSum = sum
+}
+
+func predicates(data []int32, r *results) {
+	all, some, count, maskBits, first := true, false, 0, 0, -1
+	go for i, x := range data {
+		all = all && reduce.All(x >= -20)
+		some = some || reduce.Any(x == 17)
+		count += reduce.Count(x%2 == 0)
+		maskBits += bits.OnesCount64(uint64(reduce.Mask(x > 30)))
+		if first < 0 {
+			if lane := reduce.FindFirstSet(x > 35); lane >= 0 {
+				first = reduce.From(i)[lane]
+			}
+		}
+	}
+	r.All, r.Any, r.Count, r.MaskBits, r.First = all, some, count, maskBits, first
+}
+
+// The helpers below run under the caller's execution mask: inactive lanes
+// must not contribute to the reduction.
+
+func addActive(v lanes.Varying[int32]) lanes.Varying[int64] {
+	return int64(reduce.Add(v))
+}
+
+func minActive(v lanes.Varying[int32]) lanes.Varying[int32] {
+	return reduce.Min(v)
+}
+
+func countActive(v lanes.Varying[bool]) lanes.Varying[int32] {
+	return int32(reduce.Count(v))
+}
+
+func masked(data []int32, r *results) {
+	var total int64
+	var lowest int32 = 1<<31 - 1
+	var count, evens int
+	go for _, x := range data {
+		// Active lanes receive the uniform result of the masked reduction;
+		// the others keep a value that Max/Min ignore (sums and counts are
+		// non-negative).
+		var sum lanes.Varying[int64]
+		var lo lanes.Varying[int32] = 1<<31 - 1
+		var even lanes.Varying[int32]
+		if x%3 == 0 {
+			sum = addActive(x + 20)
+			lo = minActive(x + 100)
+			even = countActive(x%2 == 0)
+		}
+		total += reduce.Max(sum)
+		lowest = min(lowest, reduce.Min(lo))
+		evens += int(reduce.Max(even))
+		count += reduce.Count(x%3 == 0)
+	}
+	r.MaskedSum, r.MaskedMin, r.MaskedCount, r.MaskedEvens = total, lowest, count, evens
+}
+
+func reference(data []int32, u []uint32, f []float32) results {
+	r := results{Prod: 1, Min: 1<<31 - 1, Max: -1 << 31, And: ^uint32(0), All: true, First: -1, MaskedMin: 1<<31 - 1}
+	for i, x := range data {
+		r.Sum += int64(x)
+		r.Prod *= int64(x&1 + 1)
+		r.Min = min(r.Min, x)
+		r.Max = max(r.Max, x)
+		r.All = r.All && x >= -20
+		r.Any = r.Any || x == 17
+		if x%2 == 0 {
+			r.Count++
+		}
+		if x > 30 {
+			r.MaskBits++
+		}
+		if r.First < 0 && x > 35 {
+			r.First = i
+		}
+		if x%3 == 0 {
+			r.MaskedSum += int64(x + 20)
+			r.MaskedMin = min(r.MaskedMin, x+100)
+			r.MaskedCount++
+			if x%2 == 0 {
+				r.MaskedEvens++
+			}
+		}
+	}
+	for _, x := range u {
+		r.Or |= x
+		r.And &= x | 0x100
+		r.Xor ^= x
+	}
+	for _, x := range f {
+		r.FSum += x
+	}
+	return r
+}
+
+func main() {
+	const n = 37 // not a multiple of any lane count: exercises the tail mask
+	data := make([]int32, n)
+	u := make([]uint32, n)
+	f := make([]float32, n)
+	for i := range n {
+		data[i] = int32(i*29%61 - 20)
+		u[i] = uint32(i) * 2654435761 >> 7
+		f[i] = float32(i*13%40-17) * 0.25
+	}
+
+	var r results
+	arith(data, &r)
+	bitwise(u, &r)
+	floats(f, &r)
+	predicates(data, &r)
+	masked(data, &r)
+
+	fmt.Printf("Add=%d Mul=%d Min=%d Max=%d\n", r.Sum, r.Prod, r.Min, r.Max)
+	fmt.Printf("Or=%#x And=%#x Xor=%#x\n", r.Or, r.And, r.Xor)
+	fmt.Printf("Add(float32)=%g\n", r.FSum)
+	fmt.Printf("All=%v Any=%v Count=%d Mask bits=%d FindFirstSet=%d\n", r.All, r.Any, r.Count, r.MaskBits, r.First)
+	fmt.Printf("Masked: Add=%d Min=%d Count=%d Count(even)=%d\n", r.MaskedSum, r.MaskedMin, r.MaskedCount, r.MaskedEvens)
+
+	if want := reference(data, u, f); r != want {
+		fmt.Printf("want %+v\n", want)
+		fmt.Println("Correctness: FAIL")
+	} else {
+		fmt.Println("Correctness: PASS")
+	}
+}
@@ -0,0 +1,166 @@
+// run -goexperiment spmd
+//
+// Shifted-index loads: s[i>>n] with a contiguous i reads each element of s
+// 2^n times in a row. The planned shifted-load expansion replaces the gather
+// with a narrow contiguous load and a shufflevector that duplicates the
+// elements, and applies the execution mask after the expansion. Until it
+// lands this example checks output only: it passes with the gather too.
+// Tables are sized exactly to the highest index read, so an over-wide load
+// in the tail would read past the end of the slice.
+package main
+
+import "fmt"
+
+const hextable = "0123456789abcdef"
+
+// expand2 duplicates every table entry: 4 int32 lanes load 2 elements.
+func expand2(table, out []int32) {
+	go for i := range out {
+		out[i] = table[i>>1]
+	}
+}
+
+// expand4 is the broadcast case at 128 bits: all 4 lanes read one element.
+func expand4(table, out []int32) {
+	go for i := range out {
+		out[i] = table[i>>2]
+	}
+}
+
+// window adds a uniform base before the shift. An odd base moves the
+// duplicated pairs across the vector boundary.
+func window(table, out []int32, base int) {
+	go for i := range out {
+		out[i] = table[(base+i)>>1]
+	}
+}
+
+// expandOdd loads only in the lanes where i is not a multiple of 3; the
+// other lanes keep their previous value.
+func expandOdd(table, out []int32) {
+	go for i := range out {
+		if i%3 != 0 {
+			out[i] = table[i>>1]
+		}
+	}
+}
+
+// Byte elements: 16 lanes at 128 bits, so i>>1 loads 8 bytes and i>>3 loads 2.
+func expandBytes2(src, dst []byte) {
+	go for i := range dst {
+		dst[i] = src[i>>1]
+	}
+}
+
+func expandBytes8(src, dst []byte) {
+	go for i := range dst {
+		dst[i] = src[i>>3]
+	}
+}
+
+// hexEncode is the lookup pattern from hex-encode: every source byte feeds
+// two output lanes.
+func hexEncode(dst, src []byte) {
+	go for i := range dst {
+		v := src[i>>1]
+		if i%2 == 0 {
+			dst[i] = hextable[v>>4]
+		} else {
+			dst[i] = hextable[v&0x0f]
+		}
+	}
+}
+
+func hexEncodeScalar(dst, src []byte) {
+	for i, v := range src {
+		dst[2*i] = hextable[v>>4]
+		dst[2*i+1] = hextable[v&0x0f]
+	}
+}
+
+func checkInt32(name string, got []int32, want func(i int) int32) bool {
+	for i := range got {
+		if w := want(i); got[i] != w {
+			fmt.Printf("%s[%d]: got %d, want %d\n", name, i, got[i], w)
+			return false
+		}
+	}
+	return true
+}
+
+func checkBytes(name string, got []byte, want func(i int) byte) bool {
+	for i := range got {
+		if w := want(i); got[i] != w {
+			fmt.Printf("%s[%d]: got %d, want %d\n", name, i, got[i], w)
+			return false
+		}
+	}
+	return true
+}
+
+func main() {
+	const n = 37 // not a multiple of any lane count: exercises the tail mask
+	table := make([]int32, 64)
+	for i := range table {
+		table[i] = int32(i*i + 3*i + 7)
+	}
+	bytes := make([]byte, 64)
+	for i := range bytes {
+		bytes[i] = byte(i*37 + 11)
+	}
+
+	ok := true
+	out := make([]int32, n)
+
+	expand2(table[:(n+1)/2], out)
+	ok = checkInt32("expand2", out, func(i int) int32 { return table[i>>1] }) && ok
+	fmt.Printf("expand2[0:6]: %v\n", out[:6])
+
+	expand4(table[:(n+3)/4], out)
+	ok = checkInt32("expand4", out, func(i int) int32 { return table[i>>2] }) && ok
+	fmt.Printf("expand4[0:6]: %v\n", out[:6])
+
+	for _, base := range []int{0, 6, 1, 3} {
+		window(table[:(base+n+1)/2], out, base)
+		ok = checkInt32(fmt.Sprintf("window(%d)", base), out, func(i int) int32 { return table[(base+i)>>1] }) && ok
+	}
+	fmt.Printf("window(3)[0:6]: %v\n", out[:6])
+
+	for i := range out {
+		out[i] = -1
+	}
+	expandOdd(table[:(n+1)/2], out)
+	ok = checkInt32("expandOdd", out, func(i int) int32 {
+		if i%3 == 0 {
+			return -1
+		}
+		return table[i>>1]
+	}) && ok
+	fmt.Printf("expandOdd[0:6]: %v\n", out[:6])
+
+	dst := make([]byte, n)
+	expandBytes2(bytes[:(n+1)/2], dst)
+	ok = checkBytes("expandBytes2", dst, func(i int) byte { return bytes[i>>1] }) && ok
+	fmt.Printf("expandBytes2[0:6]: %v\n", dst[:6])
+
+	expandBytes8(bytes[:(n+7)/8], dst)
+	ok = checkBytes("expandBytes8", dst, func(i int) byte { return bytes[i>>3] }) && ok
+	fmt.Printf("expandBytes8[0:10]: %v\n", dst[:10])
+
+	src := []byte("Shifted loads!")
+	hex := make([]byte, 2*len(src))
+	ref := make([]byte, 2*len(src))
+	hexEncode(hex, src)
+	hexEncodeScalar(ref, src)
+	if string(hex) != string(ref) {
+		fmt.Printf("hexEncode: got %s, want %s\n", hex, ref)
+		ok = false
+	}
+	fmt.Printf("hexEncode: %s\n", hex)
+
+	if ok {
+		fmt.Println("Correctness: PASS")
+	} else {
+		fmt.Println("Correctness: FAIL")
+	}
+}
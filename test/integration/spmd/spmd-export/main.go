@@ -0,0 +1,97 @@
+// run -goexperiment spmd
+//
+// Cross-package SPMD helpers through the exported SPMD ABI. The vecmath
+// package exports functions with varying parameters (//go:spmd export); this
+// package calls them from a go for loop (tail mask), from under a varying if
+// (partial mask), and from ordinary code (all-ones mask).
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"reduce"
+
+	"spmd-integration-tests/spmd-export/vecmath"
+)
+
+func main() {
+	const n = 37 // leaves a partial tail at every lane count
+	a := make([]int32, n)
+	b := make([]int32, n)
+	for i := range n {
+		a[i] = int32(i*13 - 200)
+		b[i] = int32(i * 5)
+	}
+
+	ok := true
+
+	// Called from a go for loop: the loop mask (including the tail) is passed.
+	clamped := make([]int32, n)
+	diffs := make([]int32, n)
+	go for i, v := range a {
+		clamped[i] = vecmath.Clamp(v, -50, 100)
+		diffs[i] = vecmath.AbsDiff(v, b[i])
+	}
+	for i := range n {
+		ok = ok && clamped[i] == min(max(a[i], -50), 100)
+		d := a[i] - b[i]
+		if d < 0 {
+			d = -d
+		}
+		ok = ok && diffs[i] == d
+	}
+	fmt.Printf("Clamp/AbsDiff: last=%d,%d\n", clamped[n-1], diffs[n-1])
+
+	// Called under a varying if: only the odd lanes are active in the callee.
+	odd := make([]int32, n)
+	go for i, v := range a {
+		if i%2 == 1 {
+			odd[i] = vecmath.Clamp(v, 0, 60)
+		}
+	}
+	for i := range n {
+		want := int32(0)
+		if i%2 == 1 {
+			want = min(max(a[i], 0), 60)
+		}
+		ok = ok && odd[i] == want
+	}
+	fmt.Printf("Clamp (odd lanes): last=%d\n", odd[n-1])
+
+	// Uniform result: the tail mask keeps lanes past n out of the count.
+	var above int
+	go for _, v := range a {
+		above += vecmath.CountAbove(v, 0)
+	}
+	wantAbove := 0
+	for _, v := range a {
+		if v > 0 {
+			wantAbove++
+		}
+	}
+	ok = ok && above == wantAbove
+	fmt.Printf("CountAbove: %d\n", above)
+
+	// Exported function that calls an unexported SPMD helper.
+	scaled := make([]int32, n)
+	go for i, v := range b {
+		scaled[i] = vecmath.ScaledClamp(v, 3, 0, 250)
+	}
+	for i := range n {
+		ok = ok && scaled[i] == min(max(b[i]*3, 0), 250)
+	}
+	fmt.Printf("ScaledClamp: last=%d\n", scaled[n-1])
+
+	// Called outside any go for: the callee sees all lanes active.
+	all := vecmath.CountAbove(lanes.Varying[int32](7), 5)
+	ok = ok && all == lanes.Count(lanes.Varying[int32](0))
+	sum := reduce.Add(vecmath.Clamp(lanes.Varying[int32](500), 0, 9))
+	ok = ok && sum == int32(9*lanes.Count(lanes.Varying[int32](0)))
+	fmt.Printf("Outside go for: all lanes active = %t\n", all == lanes.Count(lanes.Varying[int32](0)))
+
+	if ok {
+		fmt.Println("Correctness: PASS")
+	} else {
+		fmt.Println("Correctness: FAIL")
+	}
+}
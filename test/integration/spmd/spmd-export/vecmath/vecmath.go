@@ -0,0 +1,54 @@
+// Package vecmath is a shared library of varying helpers. Its exported
+// functions take and return lanes.Varying values, which is only allowed
+// because each one opts in with //go:spmd export.
+package vecmath
+
+import (
+	"lanes"
+	"reduce"
+)
+
+// Clamp limits every active lane of v to [lo, hi].
+//
+//go:spmd export
+func Clamp(v lanes.Varying[int32], lo, hi int32) lanes.Varying[int32] {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// AbsDiff returns |a - b| per lane.
+//
+//go:spmd export
+func AbsDiff(a, b lanes.Varying[int32]) lanes.Varying[int32] {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	return d
+}
+
+// CountAbove returns how many active lanes of v exceed t. Inactive lanes are
+// excluded by the caller's mask, so the result depends on the call site.
+//
+//go:spmd export
+func CountAbove(v lanes.Varying[int32], t int32) int {
+	return reduce.Count(v > t)
+}
+
+// scale is an ordinary unexported SPMD helper; it needs no directive.
+func scale(v lanes.Varying[int32], k int32) lanes.Varying[int32] {
+	return v * k
+}
+
+// ScaledClamp calls an unexported SPMD helper from an exported one, so the
+// hidden mask parameter is forwarded inside the library package.
+//
+//go:spmd export
+func ScaledClamp(v lanes.Varying[int32], k, lo, hi int32) lanes.Varying[int32] {
+	return Clamp(scale(v, k), lo, hi)
+}
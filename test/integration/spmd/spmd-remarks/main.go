@@ -0,0 +1,81 @@
+// Optimization remarks: one function per SPMD code generation decision that
+// tinygo -spmd-remarks reports. A "// REMARK" comment lists text that the
+// remarks for its line must contain in the 128-bit WASM build; E2E Level 18
+// checks them.
+package main
+
+import "fmt"
+
+// scale reads and writes element i in lane order, so both accesses are
+// contiguous, and it has no accumulator, so the loop is peeled.
+func scale(dst, src []int32) {
+	go for i := range len(dst) { // REMARK "loop peeled"
+		dst[i] = src[i] * 3 // REMARK "load contiguous" "store contiguous"
+	}
+}
+
+// lookup loads through an index vector: one gather per iteration.
+func lookup(dst, table, idx []int32) {
+	go for i := range len(dst) {
+		dst[i] = table[idx[i]] // REMARK "load gather" "store contiguous"
+	}
+}
+
+// spread stores through an index vector: one scatter per iteration.
+func spread(dst, src, idx []int32) {
+	go for i := range len(src) {
+		dst[idx[i]] = src[i] // REMARK "store scatter"
+	}
+}
+
+// magnitude stores to dst[i] in both branches of a varying if; the two
+// masked stores become one select and one store.
+func magnitude(dst, src []int32) {
+	go for i, v := range src {
+		if v < 0 {
+			dst[i] = -v // REMARK "store coalesced"
+		} else {
+			dst[i] = v
+		}
+	}
+}
+
+// cycle picks a value by i % 4: the select chain becomes one shuffle.
+func cycle(dst []byte) {
+	go for i := range dst {
+		pos := i % 4
+		var c byte
+		if pos == 0 { // REMARK "mux"
+			c = 'a'
+		} else if pos == 1 {
+			c = 'b'
+		} else if pos == 2 {
+			c = 'c'
+		} else {
+			c = 'd'
+		}
+		dst[i] = c
+	}
+}
+
+func main() {
+	src := []int32{3, -1, 4, -1, 5, -9, 2, -6, 5, 3}
+	idx := []int32{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}
+
+	dst := make([]int32, len(src))
+	scale(dst, src)
+	fmt.Println("scale:", dst)
+
+	lookup(dst, src, idx)
+	fmt.Println("lookup:", dst)
+
+	spread(dst, src, idx)
+	fmt.Println("spread:", dst)
+
+	magnitude(dst, src)
+	fmt.Println("magnitude:", dst)
+
+	b := make([]byte, 10)
+	cycle(b)
+	fmt.Println("cycle:", string(b))
+}
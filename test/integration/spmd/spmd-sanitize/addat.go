@@ -0,0 +1,37 @@
+// run -goexperiment spmd -target=wasi -spmd-sanitize
+
+// The histogram of conflicts.go written with lanes.AddAt, which adds the
+// lanes that share a key together before it stores. The sanitizer reports
+// nothing, and the program exits with status 1 if a count is lost.
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"os"
+)
+
+// histogramAddAt counts keys without losing the lanes that share a key.
+func histogramAddAt(counts, keys []int32) {
+	go for _, k := range keys {
+		lanes.AddAt(counts, k, 1)
+	}
+}
+
+func main() {
+	keys := []int32{1, 2, 1, 3, 0, 0, 0, 0, 2, 3, 2, 3}
+	counts := make([]int32, 4)
+	histogramAddAt(counts, keys)
+	fmt.Println("histogramAddAt:", counts)
+
+	want := make([]int32, 4)
+	for _, k := range keys {
+		want[k]++
+	}
+	for i := range want {
+		if counts[i] != want[i] {
+			fmt.Printf("count %d: got %d, want %d\n", i, counts[i], want[i])
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// run -goexperiment spmd -target=wasi -spmd-sanitize
+
+// An index that is out of range in one active lane. Go panics with or
+// without the sanitizer; -spmd-sanitize also reports the lane, the index and
+// the position before the panic.
+package main
+
+import "fmt"
+
+// storeAt sets dst[k] for each index k. The third index of the first
+// iteration is past the end of dst.
+func storeAt(dst, idx []int32) {
+	go for _, k := range idx {
+		dst[k] = 1 // SANITIZE "Lane 2 of 4: index 12 with length 10"
+	}
+}
+
+func main() {
+	dst := make([]int32, 10)
+	fmt.Println("storing")
+	storeAt(dst, []int32{3, 7, 12, 1})
+	fmt.Println("not reached")
+}
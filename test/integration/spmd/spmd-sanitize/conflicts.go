@@ -0,0 +1,85 @@
+// run -goexperiment spmd -target=wasi -spmd-sanitize
+
+// Scatter conflicts and compact stores past capacity: legal code that
+// -spmd-sanitize reports. Without the sanitizer the program runs to the end:
+// when lanes store to the same address the highest lane wins, and the
+// compact store's extra elements land in memory the program owns.
+package main
+
+import (
+	"fmt"
+	"lanes"
+)
+
+// histogram counts keys with a read-modify-write through a varying index.
+// Lanes holding the same key in one iteration lose updates: only the
+// highest lane's increment is stored.
+func histogram(counts, keys []int32) {
+	go for _, k := range keys {
+		counts[k]++ // SANITIZE "Lanes 0 and 2 of 4 store to"
+	}
+}
+
+// parity writes each value through a varying pointer to the slot for its
+// parity, so the even lanes and the odd lanes share a target.
+func parity(slots, vals []int32) {
+	go for _, v := range vals {
+		var p lanes.Varying[*int32] = &slots[v&1]
+		*p = v // SANITIZE "Lanes 0 and 2 of 4 store to"
+	}
+}
+
+// keepNegatives packs the negative values into dst. A compact store writes a
+// whole vector, so dst needs lanes.Count elements of capacity past the write
+// offset, and the second iteration has only 2.
+func keepNegatives(dst, src []int32) int {
+	n := 0
+	go for _, v := range src {
+		n += lanes.CompactStore(dst[n:], v, v < 0) // SANITIZE "Writes 4 elements at dst[0] with cap(dst) 2"
+	}
+	return n
+}
+
+// The functions below are not reported.
+
+// evens writes the even keys only. The odd lanes share indices, but they are
+// inactive.
+func evens(out, keys []int32) {
+	go for _, k := range keys {
+		if k%2 == 0 {
+			out[k] = k
+		}
+	}
+}
+
+// permute stores through a permutation: every lane has its own index.
+func permute(out, perm []int32) {
+	go for _, k := range perm {
+		out[k] = k * 10
+	}
+}
+
+func main() {
+	counts := make([]int32, 4)
+	histogram(counts, []int32{1, 2, 1, 3, 0, 0, 0, 0, 2, 3, 2, 3})
+	total := int32(0)
+	for _, c := range counts {
+		total += c
+	}
+	fmt.Println("histogram: counted", total, "of 12 keys")
+
+	slots := make([]int32, 2)
+	parity(slots, []int32{4, 5, 6, 7})
+	fmt.Println("parity:", slots)
+
+	backing := make([]int32, 12)
+	n := keepNegatives(backing[:4:4], []int32{3, -1, 4, -1, -5, 9, -2, 6})
+	fmt.Println("keepNegatives:", backing[:n])
+
+	out := make([]int32, 8)
+	evens(out, []int32{0, 1, 2, 1, 4, 3, 6, 3})
+	fmt.Println("evens:", out)
+
+	permute(out, []int32{3, 0, 2, 1, 7, 5, 6, 4})
+	fmt.Println("permute:", out)
+}
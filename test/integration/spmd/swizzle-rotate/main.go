@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"lanes"
+)
+
+// Full-width lanes.Swizzle and lanes.Rotate with runtime indices/offsets.
+// Expected results are computed from the lane count the compiler picked, so the
+// output is identical at every SIMD width (including AVX2 byte vectors, where
+// permutes must cross the 128-bit halves, and scalar mode with one lane).
+
+// swizzleBytes permutes bytes with a runtime varying index (wraps modulo lane count).
+func swizzleBytes(v lanes.Varying[uint8], idx lanes.Varying[int]) lanes.Varying[uint8] {
+	return lanes.Swizzle(v, idx)
+}
+
+// swizzleInt32 permutes 32-bit lanes with a runtime varying index.
+func swizzleInt32(v lanes.Varying[int32], idx lanes.Varying[int]) lanes.Varying[int32] {
+	return lanes.Swizzle(v, idx)
+}
+
+// swizzleInt64 permutes 64-bit lanes with a runtime varying index.
+func swizzleInt64(v lanes.Varying[int64], idx lanes.Varying[int]) lanes.Varying[int64] {
+	return lanes.Swizzle(v, idx)
+}
+
+// expectSwizzle returns the scalar reference for lanes.Swizzle applied to
+// consecutive blocks of width elements.
+func expectSwizzle[T any](in []T, perm []int, width int) []T {
+	out := make([]T, len(in))
+	for i := range in {
+		base := i - i%width
+		out[i] = in[base+perm[i]%width]
+	}
+	return out
+}
+
+// expectRotate returns the scalar reference for lanes.Rotate(v, offset):
+// lane j receives lane (j - offset) mod width of its block.
+func expectRotate[T any](in []T, offset, width int) []T {
+	out := make([]T, len(in))
+	for i := range in {
+		base := i - i%width
+		j := ((i%width-offset)%width + width) % width
+		out[i] = in[base+j]
+	}
+	return out
+}
+
+func equal[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func report(name string, ok bool) bool {
+	if ok {
+		fmt.Printf("%s: PASS\n", name)
+	} else {
+		fmt.Printf("%s: FAIL\n", name)
+	}
+	return ok
+}
+
+func main() {
+	// 64 elements: a multiple of every lane count up to 64 (AVX-512 bytes).
+	const n = 64
+	in8 := make([]uint8, n)
+	in32 := make([]int32, n)
+	in64 := make([]int64, n)
+	perm := make([]int, n)
+	for i := range n {
+		in8[i] = uint8(i*7 + 3)
+		in32[i] = int32(i*1000 - 5)
+		in64[i] = int64(i) << 40
+		perm[i] = (i*37 + 11) % n // runtime indices spanning the whole register
+	}
+
+	var w8, w32, w64 int
+	out8 := make([]uint8, n)
+	out32 := make([]int32, n)
+	out64 := make([]int64, n)
+
+	go for i, v := range in8 {
+		w8 = lanes.Count(v)
+		out8[i] = swizzleBytes(v, perm[i])
+	}
+	go for i, v := range in32 {
+		w32 = lanes.Count(v)
+		out32[i] = swizzleInt32(v, perm[i])
+	}
+	go for i, v := range in64 {
+		w64 = lanes.Count(v)
+		out64[i] = swizzleInt64(v, perm[i])
+	}
+
+	ok := true
+	ok = report("Swizzle uint8", equal(out8, expectSwizzle(in8, perm, w8))) && ok
+	ok = report("Swizzle int32", equal(out32, expectSwizzle(in32, perm, w32))) && ok
+	ok = report("Swizzle int64", equal(out64, expectSwizzle(in64, perm, w64))) && ok
+
+	// Runtime offsets, including ones larger than the lane count and negative.
+	offsets := []int{1, 3, 17, -5}
+	for _, off := range offsets {
+		go for i, v := range in8 {
+			out8[i] = lanes.Rotate(v, off)
+		}
+		go for i, v := range in32 {
+			out32[i] = lanes.Rotate(v, off)
+		}
+		ok = report(fmt.Sprintf("Rotate uint8 by %d", off), equal(out8, expectRotate(in8, off, w8))) && ok
+		ok = report(fmt.Sprintf("Rotate int32 by %d", off), equal(out32, expectRotate(in32, off, w32))) && ok
+	}
+
+	if ok {
+		fmt.Println("Correctness: PASS")
+	} else {
+		fmt.Println("Correctness: FAIL")
+	}
+}
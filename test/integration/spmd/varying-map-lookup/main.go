@@ -0,0 +1,134 @@
+// run -goexperiment spmd
+//
+// Map lookups with a varying key: m[k] and v, ok := m[k] inside go for give
+// a varying value (and a varying ok). Each active lane looks up its own key,
+// one lane at a time, and lanes that are off in the execution mask do not
+// touch the map. Every result is checked against a scalar loop, so the output
+// does not depend on the lane count.
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"os"
+	"reduce"
+)
+
+type Point struct {
+	X, Y int32
+}
+
+// translate maps each code through table. A code that is not in the table
+// gives the zero value, as a scalar lookup does.
+func translate(codes []int32, table map[int32]int32, out []int32) {
+	go for i, c := range codes {
+		out[i] = table[c]
+	}
+}
+
+// lookupIDs finds the id of each word with the comma-ok form. Unknown words
+// get -1. It returns the number of known words.
+func lookupIDs(words []string, dict map[string]int32, ids []int32) int32 {
+	var hits lanes.Varying[int32]
+	go for i, w := range words {
+		id, ok := dict[w]
+		if ok {
+			ids[i] = id
+			hits++
+		} else {
+			ids[i] = -1
+		}
+	}
+	return reduce.Add(hits)
+}
+
+// names gives each code its name: the map's value type is string, so the
+// result is a lanes.Varying[string].
+func names(codes []int32, byCode map[int32]string, out []string) {
+	go for i, c := range codes {
+		out[i] = byCode[c]
+	}
+}
+
+// terrain looks up each point of a grid keyed by a struct. Points outside
+// the grid's bounds are not looked up: their lanes are off.
+func terrain(points []Point, grid map[Point]byte, out []byte) {
+	go for i, p := range points {
+		t := byte('?')
+		if p.X >= 0 && p.Y >= 0 && p.X < 3 && p.Y < 3 {
+			t = grid[p]
+		}
+		out[i] = t
+	}
+}
+
+func main() {
+	ok := true
+	check := func(name string, got, want any) {
+		fmt.Printf("%s: %v\n", name, got)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			fmt.Printf("  want %v\n", want)
+			ok = false
+		}
+	}
+
+	// 13 codes: not a multiple of any lane count, so every build has a tail.
+	codes := []int32{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5, 8, 9}
+	table := map[int32]int32{1: 10, 2: 20, 3: 30, 5: 50, 8: 80}
+	out := make([]int32, len(codes))
+	translate(codes, table, out)
+	want := make([]int32, len(codes))
+	for i, c := range codes {
+		want[i] = table[c]
+	}
+	check("translate", out, want)
+
+	words := []string{"go", "for", "range", "lanes", "map", "if", "reduce", "chan", "go"}
+	dict := map[string]int32{"go": 1, "for": 2, "range": 3, "map": 4, "chan": 5}
+	ids := make([]int32, len(words))
+	hits := lookupIDs(words, dict, ids)
+	wantIDs := make([]int32, len(words))
+	var wantHits int32
+	for i, w := range words {
+		wantIDs[i] = -1
+		if id, found := dict[w]; found {
+			wantIDs[i] = id
+			wantHits++
+		}
+	}
+	check("lookupIDs", ids, wantIDs)
+	check("known words", hits, wantHits)
+
+	byCode := map[int32]string{1: "one", 3: "three", 5: "five", 9: "nine"}
+	named := make([]string, len(codes))
+	names(codes, byCode, named)
+	wantNames := make([]string, len(codes))
+	for i, c := range codes {
+		wantNames[i] = byCode[c]
+	}
+	check("names", named, wantNames)
+
+	grid := map[Point]byte{}
+	for y := int32(0); y < 3; y++ {
+		for x := int32(0); x < 3; x++ {
+			grid[Point{x, y}] = "#.~"[(x+y)%3]
+		}
+	}
+	points := []Point{{0, 0}, {1, 2}, {-1, 0}, {2, 2}, {3, 1}, {2, 0}, {1, 1}}
+	tiles := make([]byte, len(points))
+	terrain(points, grid, tiles)
+	wantTiles := make([]byte, len(points))
+	for i, p := range points {
+		wantTiles[i] = '?'
+		if p.X >= 0 && p.Y >= 0 && p.X < 3 && p.Y < 3 {
+			wantTiles[i] = grid[p]
+		}
+	}
+	check("terrain", string(tiles), string(wantTiles))
+
+	if !ok {
+		fmt.Println("Correctness: FAIL")
+		os.Exit(1)
+	}
+	fmt.Println("Correctness: PASS")
+}
@@ -0,0 +1,193 @@
+// run -goexperiment spmd
+//
+// Varying structs: lanes.Varying[Point] is stored as one vector per field
+// (struct of arrays), while the slices stay arrays of structs. Ranging over
+// []Point de-interleaves the fields, p.X and p.Y are plain vector operations,
+// and writing a whole struct back re-interleaves them. Coordinates are int32
+// fixed-point so every result is exact and independent of the lane count.
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"reduce"
+)
+
+type Point struct {
+	X, Y int32
+}
+
+// Particle mixes field widths and nests a struct: Pos and Vel are flattened to
+// four int32 vectors, Alive to a bool vector with the same lane count.
+type Particle struct {
+	Pos, Vel Point
+	Mass     int16
+	Alive    bool
+}
+
+// dot takes and returns varying values of struct type.
+func dot(a, b lanes.Varying[Point]) lanes.Varying[int32] {
+	return a.X*b.X + a.Y*b.Y
+}
+
+// rotate90 builds a new varying struct field by field.
+func rotate90(p lanes.Varying[Point]) lanes.Varying[Point] {
+	var r lanes.Varying[Point]
+	r.X = -p.Y
+	r.Y = p.X
+	return r
+}
+
+// lengthSq reads both fields of each point (de-interleaved load).
+func lengthSq(points []Point, out []int32) {
+	go for i, p := range points {
+		out[i] = dot(p, p)
+	}
+}
+
+// step advances every live particle and bounces it off the floor (y = 0).
+// The whole struct is written back under the execution mask, so dead
+// particles are left untouched.
+func step(particles []Particle, gravity int32) {
+	go for i, p := range particles {
+		if !p.Alive {
+			continue
+		}
+		p.Vel.Y -= gravity
+		p.Pos.X += p.Vel.X
+		p.Pos.Y += p.Vel.Y
+		if p.Pos.Y < 0 {
+			p.Pos.Y = -p.Pos.Y
+			p.Vel.Y = -p.Vel.Y * int32(p.Mass) / 16
+		}
+		particles[i] = p
+	}
+}
+
+// spin rotates every point in place, writing single fields through the slice.
+func spin(points []Point) {
+	go for i, p := range points {
+		r := rotate90(p)
+		points[i].X = r.X
+		points[i].Y = r.Y
+	}
+}
+
+// countEqual compares varying structs field by field.
+func countEqual(a, b []Point) int {
+	var n lanes.Varying[int32]
+	go for i, p := range a {
+		if p == b[i] {
+			n++
+		}
+	}
+	return int(reduce.Add(n))
+}
+
+// centroidSum reduces each field independently.
+func centroidSum(points []Point) Point {
+	var sx, sy int32
+	go for _, p := range points {
+		sx += reduce.Add(p.X)
+		sy += reduce.Add(p.Y)
+	}
+	return Point{sx, sy}
+}
+
+func stepScalar(particles []Particle, gravity int32) {
+	for i := range particles {
+		p := &particles[i]
+		if !p.Alive {
+			continue
+		}
+		p.Vel.Y -= gravity
+		p.Pos.X += p.Vel.X
+		p.Pos.Y += p.Vel.Y
+		if p.Pos.Y < 0 {
+			p.Pos.Y = -p.Pos.Y
+			p.Vel.Y = -p.Vel.Y * int32(p.Mass) / 16
+		}
+	}
+}
+
+func main() {
+	const n = 37 // not a multiple of any lane count: exercises the tail mask
+	points := make([]Point, n)
+	other := make([]Point, n)
+	particles := make([]Particle, n)
+	for i := range n {
+		points[i] = Point{int32(i*7%23 - 11), int32(i*5%17 - 8)}
+		other[i] = points[i]
+		if i%3 == 0 {
+			other[i].Y++
+		}
+		particles[i] = Particle{
+			Pos:   Point{int32(i * 10), int32(i*13%40 + 1)},
+			Vel:   Point{int32(i%5 - 2), int32(i%7 - 3)},
+			Mass:  int16(8 + i%9),
+			Alive: i%4 != 1,
+		}
+	}
+
+	ok := true
+
+	// Field reads.
+	lens := make([]int32, n)
+	lengthSq(points, lens)
+	for i, p := range points {
+		if want := p.X*p.X + p.Y*p.Y; lens[i] != want {
+			fmt.Printf("lengthSq[%d]: got %d, want %d\n", i, lens[i], want)
+			ok = false
+		}
+	}
+	fmt.Printf("lengthSq[0:4]: %v\n", lens[:4])
+
+	// Struct comparison.
+	if got, want := countEqual(points, other), n-(n+2)/3; got != want {
+		fmt.Printf("countEqual: got %d, want %d\n", got, want)
+		ok = false
+	}
+
+	// Field reductions.
+	var want Point
+	for _, p := range points {
+		want.X += p.X
+		want.Y += p.Y
+	}
+	sum := centroidSum(points)
+	if sum != want {
+		fmt.Printf("centroidSum: got %v, want %v\n", sum, want)
+		ok = false
+	}
+	fmt.Printf("Centroid sum: %v\n", sum)
+
+	// Per-field stores.
+	rotated := append([]Point(nil), points...)
+	spin(rotated)
+	for i, p := range points {
+		if r := rotated[i]; r.X != -p.Y || r.Y != p.X {
+			fmt.Printf("spin[%d]: got %v, want {%d %d}\n", i, r, -p.Y, p.X)
+			ok = false
+		}
+	}
+
+	// Whole-struct stores with a mixed-width, nested struct.
+	ref := append([]Particle(nil), particles...)
+	for range 10 {
+		step(particles, 2)
+		stepScalar(ref, 2)
+	}
+	for i := range particles {
+		if particles[i] != ref[i] {
+			fmt.Printf("step[%d]: got %+v, want %+v\n", i, particles[i], ref[i])
+			ok = false
+		}
+	}
+	fmt.Printf("Particle 2 after 10 steps: %+v\n", particles[2])
+
+	if ok {
+		fmt.Println("Correctness: PASS")
+	} else {
+		fmt.Println("Correctness: FAIL")
+	}
+}
@@ -0,0 +1,103 @@
+// vet -goexperiment spmd -spmdlanewidth
+
+// spmdlanewidth: legal code whose result depends on a lane count that
+// differs between targets. Each function prints the intended values when
+// lanes.Varying[int] has 4 lanes (wasm, 4-byte int) and something else with
+// 8-byte int at 128 bits, where it has 2.
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"reduce"
+)
+
+// scaled uses lanes.Index() as the element index. It is only equal to i
+// while go for range 4 runs in one iteration.
+func scaled(data *[4]int) {
+	go for i := range 4 {
+		data[i] = 10 * lanes.Index() // WANT "lanes.Index() equals i only in the first iteration of go for range 4"
+	}
+}
+
+// doubled reads a varying after the loop, which keeps only the lanes of the
+// last iteration.
+func doubled(src *[4]int) []int {
+	var v lanes.Varying[int]
+	go for i := range 4 {
+		v = src[i] * 2 // WANT "v keeps only the last iteration of go for range 4"
+	}
+	return reduce.From(v)
+}
+
+// fourthLanes sums the elements that fall in lane 3.
+func fourthLanes(data []int) int {
+	var hits lanes.Varying[int]
+	go for _, x := range data {
+		if lanes.Index() == 3 { // WANT "lanes.Index() == 3 is never true"
+			hits += x
+		}
+	}
+	return reduce.Add(hits)
+}
+
+func lastLane(v lanes.Varying[int]) int {
+	return reduce.From(v)[3] // WANT "index 3 is out of range"
+}
+
+func allPositive(v lanes.Varying[int32]) bool {
+	return reduce.Mask(v > 0) == 0xF // WANT "reduce.Mask(...) == 0xF means all lanes only when lanes.Varying[int32] has 4 lanes"
+}
+
+// The functions below are portable and are not reported.
+
+// pairs fits in one iteration in every configuration: int has at least 2
+// lanes at 128 bits.
+func pairs(data *[2]int) {
+	go for i := range 2 {
+		data[i] = 10 * lanes.Index()
+	}
+}
+
+// firstLanes compares with lane 0, which exists everywhere.
+func firstLanes(data []int) int {
+	var hits lanes.Varying[int]
+	go for _, x := range data {
+		if lanes.Index() == 0 {
+			hits += x
+		}
+	}
+	return reduce.Add(hits)
+}
+
+// sumLanes walks every lane of reduce.From instead of a fixed count.
+func sumLanes(v lanes.Varying[int]) int {
+	total := 0
+	for _, x := range reduce.From(v) {
+		total += x
+	}
+	return total
+}
+
+func allNonNegative(v lanes.Varying[int32]) bool {
+	return reduce.All(v >= 0)
+}
+
+func main() {
+	var a [4]int
+	scaled(&a)
+	fmt.Println("scaled:", a)
+	fmt.Println("doubled:", doubled(&[4]int{1, 2, 3, 4}))
+
+	data := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	fmt.Println("fourthLanes:", fourthLanes(data), "firstLanes:", firstLanes(data))
+
+	var b [2]int
+	pairs(&b)
+	fmt.Println("pairs:", b)
+
+	go for _, x := range data {
+		fmt.Println("lastLane:", lastLane(x), "sumLanes:", sumLanes(x))
+		fmt.Println("allPositive:", allPositive(int32(x)), "allNonNegative:", allNonNegative(int32(x)))
+	}
+}
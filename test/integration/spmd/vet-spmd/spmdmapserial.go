@@ -0,0 +1,64 @@
+// vet -goexperiment spmd -spmdmapserial
+
+// spmdmapserial: varying map keys are illegal, so code converts them with
+// reduce.From and walks the lanes one by one. The map work then runs once
+// per lane, as scalar code, inside the go for body.
+package main
+
+import (
+	"fmt"
+	"lanes"
+	"reduce"
+)
+
+func translate(codes []int32, table map[int32]int32, out []int32) {
+	go for i, c := range codes {
+		keys := reduce.From(c)
+		vals := make([]int32, len(keys))
+		for j, k := range keys {
+			vals[j] = table[k] // WANT "map access for each lane of reduce.From(c) serializes the go for body"
+		}
+		out[i] = lanes.From(vals)
+	}
+}
+
+func forget(codes []int32, seen map[int32]bool) {
+	go for _, c := range codes {
+		for j := range lanes.Count(c) {
+			delete(seen, reduce.From(c)[j]) // WANT "map access for each lane of reduce.From(c) serializes the go for body"
+		}
+	}
+}
+
+// Not reported: the key is uniform, so the map is touched once per
+// iteration whatever the lane count.
+func tally(codes []int32, counts map[string]int) {
+	go for _, c := range codes {
+		counts["positive"] += reduce.Count(c > 0)
+	}
+}
+
+// Not reported: the lookup table is built outside the loop and indexed with
+// a varying index.
+func translateTable(codes []int32, table []int32, out []int32) {
+	go for i, c := range codes {
+		out[i] = table[c]
+	}
+}
+
+func main() {
+	codes := []int32{1, 2, 3, 2, 1, 0, 3, 3}
+	table := map[int32]int32{0: 100, 1: 101, 2: 102, 3: 103}
+	out := make([]int32, len(codes))
+	translate(codes, table, out)
+	fmt.Println("translate:", out)
+
+	translateTable(codes, []int32{100, 101, 102, 103}, out)
+	fmt.Println("translateTable:", out)
+
+	seen := map[int32]bool{0: true, 1: true, 2: true, 3: true, 4: true}
+	forget(codes, seen)
+	counts := map[string]int{}
+	tally(codes, counts)
+	fmt.Println("left:", len(seen), "positive:", counts["positive"])
+}
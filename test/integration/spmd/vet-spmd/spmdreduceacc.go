@@ -0,0 +1,75 @@
+// vet -goexperiment spmd -spmdreduceacc
+
+// spmdreduceacc: a uniform accumulator updated with a reduction in every
+// iteration of a go for loop. The result is right, but each iteration pays
+// for a cross-lane reduction that a varying accumulator would do once.
+package main
+
+import (
+	"fmt"
+	"math"
+	"reduce"
+)
+
+func sum(data []int32) int64 {
+	var total int64
+	go for _, x := range data {
+		total += int64(reduce.Add(x)) // WANT "uniform accumulator total is updated with reduce.Add every iteration"
+	}
+	return total
+}
+
+func product(data []int32) int32 {
+	p := int32(1)
+	go for _, x := range data {
+		p = p * reduce.Mul(x) // WANT "uniform accumulator p is updated with reduce.Mul every iteration"
+	}
+	return p
+}
+
+func highest(data []int32) int32 {
+	hi := int32(math.MinInt32)
+	go for _, x := range data {
+		hi = max(hi, reduce.Max(x)) // WANT "uniform accumulator hi is updated with reduce.Max every iteration"
+	}
+	return hi
+}
+
+func countEven(data []int32) int {
+	n := 0
+	go for _, x := range data {
+		n += reduce.Count(x%2 == 0) // WANT "uniform accumulator n is updated with reduce.Count every iteration"
+	}
+	return n
+}
+
+// The functions below are not reported.
+
+// offsets reads the running total in the loop body: each group needs the
+// total of the groups before it.
+func offsets(data []int32, out []int64) {
+	var running int64
+	go for i, x := range data {
+		out[i] = running + int64(x)
+		running += int64(reduce.Add(x))
+	}
+}
+
+// flagged reduces into a fresh variable each iteration; nothing accumulates.
+func flagged(data []int32, out []bool) {
+	go for i, x := range data {
+		neg := reduce.Any(x < 0)
+		out[i] = neg
+	}
+}
+
+func main() {
+	data := []int32{3, 1, 4, 1, 5, 9, 2, 6, 5, 3}
+	fmt.Println("sum:", sum(data), "product:", product(data), "highest:", highest(data), "even:", countEven(data))
+
+	out := make([]int64, len(data))
+	offsets(data, out)
+	neg := make([]bool, len(data))
+	flagged(data, neg)
+	fmt.Println("offsets:", out[0], "flagged:", neg[0])
+}
@@ -0,0 +1,55 @@
+// vet -goexperiment spmd -spmdreduceloop
+
+// spmdreduceloop: reductions in a go for body that run every iteration and
+// are not an early exit.
+package main
+
+import (
+	"fmt"
+	"reduce"
+)
+
+const debug = false
+
+func anyNegative(data []int32) bool {
+	found := false
+	go for _, x := range data {
+		found = found || reduce.Any(x < 0) // WANT "reduce.Any runs every iteration of the go for body"
+	}
+	return found
+}
+
+func trace(data []int32) {
+	go for _, x := range data {
+		fmt.Println(reduce.From(x)) // WANT "reduce.From runs every iteration of the go for body"
+	}
+}
+
+// The functions below are not reported.
+
+// indexOf leaves the loop on the first match: the reductions in the exit
+// branch run once.
+func indexOf(data []int32, target int32) int {
+	go for i, x := range data {
+		if reduce.Any(x == target) {
+			return reduce.From(i)[reduce.FindFirstSet(x == target)]
+		}
+	}
+	return -1
+}
+
+// checked only prints when the debug constant is set.
+func checked(data []int32) {
+	go for _, x := range data {
+		if debug {
+			fmt.Println(reduce.From(x))
+		}
+	}
+}
+
+func main() {
+	data := []int32{3, 1, 4, 1, 5, 9, 2, 6}
+	fmt.Println("anyNegative:", anyNegative(data), "indexOf(9):", indexOf(data, 9))
+	trace(data[:2])
+	checked(data)
+}
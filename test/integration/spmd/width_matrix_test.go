@@ -0,0 +1,453 @@
+// SPMD Width-Matrix Differential Tests
+// Builds every width-independent run-pass example at each virtual SIMD width,
+// in scalar mode, for WASM and x86-64, and diffs stdout against a golden file
+// Part of Phase 2.9e - Virtual SIMD Register Width
+
+package spmd_integration_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"text/tabwriter"
+)
+
+var (
+	updateGolden = flag.Bool("update", false, "rewrite width-matrix golden files from the scalar WASM build")
+	matrixWidths = flag.String("widths", "32,64,128,256,512", "comma-separated virtual SIMD widths in bits for TestSPMDWidthMatrix")
+	ssadumpPath  = flag.String("ssadump", "", "SPMD-aware ssadump; adds an interp column (go/ssa/interp) at every width to TestSPMDWidthMatrix")
+)
+
+// Examples left out of the width matrix, with the reason. Every other
+// example in basicExamples and proposedExamples is in the matrix and needs a
+// golden file. Examples that print varying values with %v or compute with
+// lanes.Index() (Level 9 of test/e2e/spmd-e2e-test.sh) change output by design.
+var widthMatrixExcluded = map[string]string{
+	"bit-counting":             "prints varying values with %v",
+	"array-counting":           "prints varying values with %v",
+	"hex-encode":               "prints per-run benchmark timings",
+	"debug-varying":            "prints varying values with %v",
+	"goroutine-varying":        "needs -scheduler=asyncify",
+	"defer-varying":            "prints lanes.Index() values",
+	"panic-recover-varying":    "prints varying values with %v",
+	"map-restrictions":         "batches map keys by lanes.Count()",
+	"pointer-varying":          "computes with lanes.Index()",
+	"type-switch-varying":      "sums a broadcast value over every lane",
+	"non-spmd-varying-return":  "prints lanes.Index() values",
+	"spmd-call-contexts":       "needs -scheduler=asyncify",
+	"lanes-index-restrictions": "prints lanes.Index() values",
+	"union-type-generics":      "prints varying values with %v",
+	"type-casting-varying":     "computes with lanes.Index()",
+	"varying-array-iteration":  "prints lanes.Index() values",
+	"mandelbrot":               "prints computation times",
+}
+
+// widthMatrixExamples returns the examples of the width matrix in
+// basicExamples, then proposedExamples, order.
+func widthMatrixExamples() []string {
+	var examples []string
+	for _, list := range [][]string{basicExamples, proposedExamples} {
+		for _, example := range list {
+			if _, excluded := widthMatrixExcluded[example]; !excluded {
+				examples = append(examples, example)
+			}
+		}
+	}
+	return examples
+}
+
+func isProposedExample(example string) bool {
+	for _, p := range proposedExamples {
+		if p == example {
+			return true
+		}
+	}
+	return false
+}
+
+// Extra build flags for an example, added at every width. The interp column
+// skips these examples: ssadump has no equivalent flags.
+var widthMatrixFlags = map[string][]string{
+	"deterministic-fp": {"-spmd-deterministic-fp"},
+}
+
+const widthMatrixGoldenDir = "testdata/width-matrix"
+
+// Benchmark timing lines differ from run to run; Level 8 of
+// spmd-e2e-test.sh drops the same prefixes.
+var nondeterministicPrefixes = []string{"Scalar:", "SPMD:", "Speedup:"}
+
+// matrixTarget is one column of the matrix: a compiler configuration and
+// the platform its binaries run on.
+type matrixTarget struct {
+	width  string // "scalar", "native" or a virtual width in bits
+	target string // "wasm", "x86" or "interp"
+	flags  []string
+}
+
+// matrixDivergence is one failing cell of the matrix. line is the first
+// differing output line (1-based), or 0 when the build or run failed.
+type matrixDivergence struct {
+	example, width, target string
+	line                   int
+	got, want              string
+}
+
+// widthMatrixTargets returns the matrix columns. Without virtualWidths only
+// the scalar and native columns are built.
+func widthMatrixTargets(virtualWidths bool) []matrixTarget {
+	var widths []string
+	for _, w := range strings.Split(*matrixWidths, ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			widths = append(widths, w)
+		}
+	}
+
+	type platform struct {
+		target string
+		flags  []string
+	}
+	platforms := []platform{{"wasm", []string{"-target=wasi"}}}
+	hasX86 := runtime.GOOS == "linux" && runtime.GOARCH == "amd64"
+	if hasX86 {
+		platforms = append(platforms, platform{"x86", []string{"-llvm-features=+ssse3,+sse4.2"}})
+	}
+
+	withFlag := func(flags []string, flag string) []string {
+		return append(append([]string(nil), flags...), flag)
+	}
+	var targets []matrixTarget
+	for _, p := range platforms {
+		targets = append(targets, matrixTarget{"scalar", p.target, withFlag(p.flags, "-simd=false")})
+		if !virtualWidths {
+			continue
+		}
+		for _, w := range widths {
+			targets = append(targets, matrixTarget{w, p.target, withFlag(p.flags, "-simd-width="+w)})
+		}
+	}
+	if hasX86 && hostHasAVX2() {
+		targets = append(targets, matrixTarget{"native", "x86", []string{"-llvm-features=+ssse3,+sse4.2,+avx2"}})
+	}
+	// The interpreter has no scalar mode: one lane is just another width.
+	if *ssadumpPath != "" {
+		for _, w := range widths {
+			targets = append(targets, matrixTarget{w, "interp", []string{"-simd-width=" + w}})
+		}
+	}
+	return targets
+}
+
+func hostHasAVX2() bool {
+	cpuinfo, err := os.ReadFile("/proc/cpuinfo")
+	return err == nil && strings.Contains(string(cpuinfo), " avx2")
+}
+
+// tinygoAcceptsFlag reports whether TinyGo knows flag, by building
+// simple-sum with it. Any build failure other than an undefined flag counts
+// as accepted, so the matrix reports it.
+func tinygoAcceptsFlag(flag, dir string) bool {
+	cmd := exec.Command(tinygoPath, "build", "-target=wasi", "-scheduler=none", flag,
+		"-o", filepath.Join(dir, "flag-probe.wasm"), "./simple-sum")
+	cmd.Env = append(os.Environ(), "GOEXPERIMENT=spmd")
+	output, _ := cmd.CombinedOutput()
+	return undefinedFlag(string(output)) == ""
+}
+
+func buildMatrixBinary(example string, target matrixTarget, dir string) (string, error) {
+	if target.target == "interp" {
+		// ssadump builds the SSA itself when it runs.
+		return "./" + example, nil
+	}
+
+	env := os.Environ()
+	env = append(env, "GOEXPERIMENT=spmd")
+
+	output := filepath.Join(dir, fmt.Sprintf("%s-%s-%s", example, target.target, target.width))
+	if target.target == "wasm" {
+		output += ".wasm"
+	}
+
+	args := []string{"build", "-scheduler=none"}
+	args = append(args, target.flags...)
+	args = append(args, widthMatrixFlags[example]...)
+	args = append(args, "-o", output, "./"+example)
+
+	cmd := exec.Command(tinygoPath, args...)
+	cmd.Env = env
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("compilation failed: %v\nOutput: %s", err, out)
+	}
+	return output, nil
+}
+
+func runMatrixBinary(binary string, target matrixTarget) ([]byte, error) {
+	var cmd *exec.Cmd
+	switch {
+	case target.target == "interp":
+		args := append([]string{"-run"}, target.flags...)
+		cmd = exec.Command(*ssadumpPath, append(args, binary)...)
+		cmd.Env = append(os.Environ(), "GOEXPERIMENT=spmd")
+	case target.target != "wasm":
+		cmd = exec.Command(binary)
+	case commandAvailable("wasmtime"):
+		cmd = exec.Command("wasmtime", "run", binary)
+	default:
+		runner := filepath.Join(projectRoot, "test/e2e/run-wasm.mjs")
+		cmd = exec.Command("node", "--experimental-wasi-unstable-preview1", runner, binary)
+	}
+	return cmd.CombinedOutput()
+}
+
+func commandAvailable(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// normalizeMatrixOutput splits program output into lines, dropping runtime
+// warnings and benchmark timing lines.
+func normalizeMatrixOutput(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if strings.Contains(line, "ExperimentalWarning") || strings.Contains(line, "trace-warnings") {
+			continue
+		}
+		skip := false
+		for _, prefix := range nondeterministicPrefixes {
+			if strings.HasPrefix(line, prefix) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// firstDifference returns the first line (1-based) where got and want
+// differ, with both sides. A missing line is reported as "<EOF>".
+func firstDifference(got, want []string) (line int, gotLine, wantLine string, differ bool) {
+	for i := 0; i < len(got) || i < len(want); i++ {
+		g, w := "<EOF>", "<EOF>"
+		if i < len(got) {
+			g = got[i]
+		}
+		if i < len(want) {
+			w = want[i]
+		}
+		if g != w {
+			return i + 1, g, w, true
+		}
+	}
+	return 0, "", "", false
+}
+
+func formatDivergences(divergences []matrixDivergence) string {
+	sort.Slice(divergences, func(i, j int) bool {
+		a, b := divergences[i], divergences[j]
+		if a.example != b.example {
+			return a.example < b.example
+		}
+		if a.target != b.target {
+			return a.target < b.target
+		}
+		return a.width < b.width
+	})
+
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "EXAMPLE\tWIDTH\tTARGET\tLINE\tGOT\tWANT")
+	for _, d := range divergences {
+		line := "-"
+		if d.line > 0 {
+			line = fmt.Sprint(d.line)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%q\t%q\n", d.example, d.width, d.target, line, d.got, d.want)
+	}
+	tw.Flush()
+	return sb.String()
+}
+
+func TestSPMDWidthMatrix(t *testing.T) {
+	checkTinyGo(t)
+
+	// -simd-width is planned in PLAN.md 2.9e. Without it TinyGo rejects every
+	// virtual-width build, which says nothing about the examples.
+	virtualWidths := tinygoAcceptsFlag("-simd-width=128", t.TempDir())
+	if !virtualWidths {
+		t.Log("TinyGo does not accept -simd-width: skipping the virtual-width columns")
+	}
+	targets := widthMatrixTargets(virtualWidths)
+	examples := widthMatrixExamples()
+	var (
+		mu          sync.Mutex
+		divergences []matrixDivergence
+		attempted   int
+	)
+	// Each example records its cells once it has run them all, so a skipped
+	// example leaves nothing behind.
+	record := func(builds int, ds []matrixDivergence) {
+		mu.Lock()
+		attempted += builds
+		divergences = append(divergences, ds...)
+		mu.Unlock()
+	}
+
+	t.Run("matrix", func(t *testing.T) {
+		for _, example := range examples {
+			example := example // capture loop variable
+			t.Run(example, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := os.Stat(example); os.IsNotExist(err) {
+					t.Skipf("Example %s not found", example)
+				}
+				goldenPath := filepath.Join(widthMatrixGoldenDir, example+".golden")
+				golden, err := os.ReadFile(goldenPath)
+				if err != nil && !*updateGolden {
+					t.Skipf("No golden file for %s (run with -update): %v", example, err)
+				}
+				want := normalizeMatrixOutput(string(golden))
+				dir := t.TempDir()
+				for _, flag := range widthMatrixFlags[example] {
+					if !tinygoAcceptsFlag(flag, dir) {
+						t.Skipf("TinyGo does not accept %s", flag)
+					}
+				}
+
+				var (
+					builds int
+					found  []matrixDivergence
+				)
+				for _, target := range targets {
+					if target.target == "interp" && len(widthMatrixFlags[example]) > 0 {
+						continue
+					}
+					builds++
+					binary, err := buildMatrixBinary(example, target, dir)
+					if err != nil {
+						if isProposedExample(example) {
+							t.Skipf("Proposed example %s does not build yet: %s", example, firstLine(err.Error()))
+						}
+						found = append(found, matrixDivergence{example, target.width, target.target, 0, firstLine(err.Error()), "build ok"})
+						continue
+					}
+					output, err := runMatrixBinary(binary, target)
+					if err != nil {
+						found = append(found, matrixDivergence{example, target.width, target.target, 0, fmt.Sprintf("run failed: %v", err), "exit 0"})
+						continue
+					}
+					got := normalizeMatrixOutput(string(output))
+
+					if *updateGolden && target.width == "scalar" && target.target == "wasm" {
+						if err := os.WriteFile(goldenPath, []byte(strings.Join(got, "\n")+"\n"), 0o644); err != nil {
+							t.Fatalf("Failed to write %s: %v", goldenPath, err)
+						}
+						want = got
+						t.Logf("Updated %s", goldenPath)
+						continue
+					}
+					if line, g, w, differ := firstDifference(got, want); differ {
+						found = append(found, matrixDivergence{example, target.width, target.target, line, g, w})
+					}
+				}
+				record(builds, found)
+			})
+		}
+	})
+
+	if len(divergences) > 0 {
+		t.Errorf("%d of %d builds diverge from the golden output:\n%s",
+			len(divergences), attempted, formatDivergences(divergences))
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// undefinedFlag returns the flag that TinyGo's flag parser rejected in the
+// build output, or "" when the build failed for another reason.
+func undefinedFlag(output string) string {
+	const msg = "flag provided but not defined: "
+	_, rest, ok := strings.Cut(output, msg)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(firstLine(rest))
+}
+
+// TestSPMDWidthMatrixExamples checks that every matrix example has a golden
+// file and that every exclusion names a known example.
+func TestSPMDWidthMatrixExamples(t *testing.T) {
+	for _, example := range widthMatrixExamples() {
+		goldenPath := filepath.Join(widthMatrixGoldenDir, example+".golden")
+		if _, err := os.Stat(goldenPath); err != nil {
+			t.Errorf("Width-matrix example %s has no golden file: %v", example, err)
+		}
+	}
+
+	known := make(map[string]bool)
+	for _, list := range [][]string{basicExamples, proposedExamples} {
+		for _, example := range list {
+			known[example] = true
+		}
+	}
+	for example := range widthMatrixExcluded {
+		if !known[example] {
+			t.Errorf("widthMatrixExcluded names %s, which is not in basicExamples or proposedExamples", example)
+		}
+	}
+}
+
+func TestSPMDWidthMatrixDiff(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want string
+		line      int
+		g, w      string
+	}{
+		{"identical", "a\nb\n", "a\nb\n", 0, "", ""},
+		{"changed line", "a\nx\nc\n", "a\nb\nc\n", 2, "x", "b"},
+		{"short output", "a\n", "a\nb\n", 2, "<EOF>", "b"},
+		{"extra output", "a\nb\nc\n", "a\nb\n", 3, "c", "<EOF>"},
+		{"timing lines ignored", "Sum: 1\nScalar: 10ns/iter\nSpeedup: 2.00x\n", "Sum: 1\n", 0, "", ""},
+	}
+	for _, tt := range tests {
+		tt := tt // capture loop variable
+		t.Run(tt.name, func(t *testing.T) {
+			line, g, w, differ := firstDifference(normalizeMatrixOutput(tt.got), normalizeMatrixOutput(tt.want))
+			if differ != (tt.line != 0) || line != tt.line || g != tt.g || w != tt.w {
+				t.Errorf("firstDifference = %d, %q, %q, %v; want %d, %q, %q", line, g, w, differ, tt.line, tt.g, tt.w)
+			}
+		})
+	}
+
+	for _, tt := range []struct{ output, flag string }{
+		{"flag provided but not defined: -simd-width\nUsage of tinygo:\n", "-simd-width"},
+		{"main.go:3:1: syntax error\n", ""},
+	} {
+		if got := undefinedFlag(tt.output); got != tt.flag {
+			t.Errorf("undefinedFlag(%q) = %q, want %q", tt.output, got, tt.flag)
+		}
+	}
+
+	table := formatDivergences([]matrixDivergence{
+		{"simple-sum", "256", "wasm", 1, "Sum: 120", "Sum: 136"},
+		{"lo-sum", "scalar", "x86", 0, "compilation failed: exit status 1", "build ok"},
+	})
+	if !strings.HasPrefix(table, "EXAMPLE") || strings.Index(table, "lo-sum") > strings.Index(table, "simple-sum") {
+		t.Errorf("unexpected divergence table:\n%s", table)
+	}
+}
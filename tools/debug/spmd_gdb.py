@@ -0,0 +1,159 @@
+# gdb support for SPMD Go programs built by TinyGo.
+#
+# It reads the DWARF proposed in
+# docs/superpowers/specs/2026-10-16-spmd-dwarf-design.md, which TinyGo does
+# not emit yet: each lanes.Varying[T] is a typedef of that name for a vector
+# of lanes (a struct of vectors for a varying struct), and the execution mask
+# is the artificial local ".mask". This script adds:
+#
+#   print v            [10 20 30 40], in fmt's %v format
+#   print $spmd_mask() [false false true true]
+#   info spmd          the mask and every varying local of the selected
+#                      frame, with inactive lanes shown as _
+#
+# Lanes are ordinary array elements: "print v[2]", "print p.X[2]".
+#
+# Load it with: source tools/debug/spmd_gdb.py
+
+import gdb
+
+VARYING_PREFIX = "lanes.Varying["
+MASK_NAME = ".mask"
+
+
+def is_varying(t):
+    """Reports whether t is a lanes.Varying typedef, through const and
+    volatile qualifiers."""
+    t = t.unqualified()
+    while t.code == gdb.TYPE_CODE_TYPEDEF:
+        if t.name and t.name.startswith(VARYING_PREFIX):
+            return True
+        t = t.target().unqualified()
+    return False
+
+
+def lane_count(t):
+    """Returns the number of lanes of the lanes.Varying type t."""
+    t = t.strip_typedefs()
+    if t.code == gdb.TYPE_CODE_STRUCT:
+        # A varying struct is a struct of varying fields, one vector each.
+        return lane_count(t.fields()[0].type)
+    lo, hi = t.range()
+    return hi - lo + 1
+
+
+def lane(val, i):
+    """Returns lane i of the varying value val, as a string."""
+    t = val.type.strip_typedefs()
+    if t.code == gdb.TYPE_CODE_STRUCT:
+        return "{" + " ".join(lane(val[f], i) for f in t.fields()) + "}"
+    return format_scalar(val[i])
+
+
+def format_scalar(val):
+    t = val.type.strip_typedefs()
+    if t.code == gdb.TYPE_CODE_BOOL:
+        return "true" if int(val) else "false"
+    if t.code == gdb.TYPE_CODE_INT:
+        return str(int(val))
+    if t.code == gdb.TYPE_CODE_FLT:
+        return str(float(val))
+    if t.code == gdb.TYPE_CODE_PTR:
+        return hex(int(val))
+    if t.code == gdb.TYPE_CODE_STRUCT:
+        return "{" + " ".join(format_scalar(val[f]) for f in t.fields()) + "}"
+    return str(val)
+
+
+def format_varying(val, mask=None):
+    """Formats val like fmt's %v: one entry per lane, and _ for a lane that
+    is off in mask."""
+    out = []
+    for i in range(lane_count(val.type)):
+        if mask is not None and not int(mask[i]):
+            out.append("_")
+        else:
+            out.append(lane(val, i))
+    return "[" + " ".join(out) + "]"
+
+
+class VaryingPrinter:
+    def __init__(self, val):
+        self.val = val
+
+    def to_string(self):
+        try:
+            return format_varying(self.val)
+        except gdb.error as e:
+            return "<%s>" % e
+
+
+def lookup_varying(val):
+    if is_varying(val.type):
+        return VaryingPrinter(val)
+    return None
+
+
+def frame_locals(frame):
+    """Yields the symbols visible in frame, innermost first, skipping
+    shadowed names."""
+    seen = set()
+    block = frame.block()
+    while block is not None:
+        for sym in block:
+            if (sym.is_variable or sym.is_argument) and sym.name not in seen:
+                seen.add(sym.name)
+                yield sym
+        if block.function is not None:
+            break
+        block = block.superblock
+
+
+def frame_mask(frame):
+    for sym in frame_locals(frame):
+        if sym.name == MASK_NAME:
+            return sym.value(frame)
+    return None
+
+
+class SPMDMaskFunction(gdb.Function):
+    """$spmd_mask() returns the execution mask of the selected frame."""
+
+    def __init__(self):
+        super().__init__("spmd_mask")
+
+    def invoke(self):
+        mask = frame_mask(gdb.selected_frame())
+        if mask is None:
+            raise gdb.GdbError("no SPMD execution mask in this frame")
+        return mask
+
+
+class InfoSPMD(gdb.Command):
+    """Print the execution mask and the varying locals of the selected
+    frame. Lanes that are off in the mask are shown as _."""
+
+    def __init__(self):
+        super().__init__("info spmd", gdb.COMMAND_STATUS)
+
+    def invoke(self, arg, from_tty):
+        frame = gdb.selected_frame()
+        mask = frame_mask(frame)
+        if mask is None:
+            print("No SPMD execution mask in this frame.")
+            return
+        print("mask = %s" % format_varying(mask))
+        for sym in frame_locals(frame):
+            if sym.name == MASK_NAME or not is_varying(sym.type):
+                continue
+            try:
+                val = sym.value(frame)
+                m = mask if lane_count(val.type) == lane_count(mask.type) else None
+                print("%s = %s" % (sym.name, format_varying(val, m)))
+            except gdb.error as e:
+                print("%s = <%s>" % (sym.name, e))
+
+
+gdb.pretty_printers.append(lookup_varying)
+SPMDMaskFunction()
+InfoSPMD()
@@ -0,0 +1,87 @@
+# lldb support for SPMD Go programs built by TinyGo: the lldb counterpart of
+# spmd_gdb.py. It adds:
+#
+#   frame variable v   (lanes.Varying[int32]) v = [10 20 30 40]
+#   spmd               the execution mask and every varying local of the
+#                      selected frame, with inactive lanes shown as _
+#
+# Lanes are ordinary array elements: "frame variable v[2]".
+#
+# Load it with: command script import tools/debug/spmd_lldb.py
+
+import lldb
+
+VARYING_PREFIX = "lanes.Varying["
+MASK_NAME = ".mask"
+
+
+def is_varying(valobj):
+    t = valobj.GetType()
+    while t.IsTypedefType():
+        if t.GetName().startswith(VARYING_PREFIX):
+            return True
+        t = t.GetTypedefedType()
+    return False
+
+
+def is_struct(valobj):
+    return valobj.GetType().GetCanonicalType().GetTypeClass() in (
+        lldb.eTypeClassStruct, lldb.eTypeClassClass)
+
+
+def lane_count(valobj):
+    if is_struct(valobj):
+        # A varying struct is a struct of varying fields, one vector each.
+        return lane_count(valobj.GetChildAtIndex(0))
+    return valobj.GetNumChildren()
+
+
+def lane(valobj, i):
+    if is_struct(valobj):
+        fields = [valobj.GetChildAtIndex(f) for f in range(valobj.GetNumChildren())]
+        return "{" + " ".join(lane(f, i) for f in fields) + "}"
+    return format_scalar(valobj.GetChildAtIndex(i))
+
+
+def format_scalar(valobj):
+    t = valobj.GetType().GetCanonicalType()
+    if t.GetBasicType() == lldb.eBasicTypeBool:
+        return "true" if valobj.GetValueAsUnsigned() else "false"
+    if is_struct(valobj):
+        fields = [valobj.GetChildAtIndex(f) for f in range(valobj.GetNumChildren())]
+        return "{" + " ".join(format_scalar(f) for f in fields) + "}"
+    return valobj.GetValue() or valobj.GetSummary() or "?"
+
+
+def format_varying(valobj, mask=None):
+    out = []
+    for i in range(lane_count(valobj)):
+        if mask is not None and not mask.GetChildAtIndex(i).GetValueAsUnsigned():
+            out.append("_")
+        else:
+            out.append(lane(valobj, i))
+    return "[" + " ".join(out) + "]"
+
+
+def varying_summary(valobj, internal_dict):
+    return format_varying(valobj.GetNonSyntheticValue())
+
+
+def info_spmd(debugger, command, result, internal_dict):
+    frame = debugger.GetSelectedTarget().GetProcess().GetSelectedThread().GetSelectedFrame()
+    mask = frame.FindVariable(MASK_NAME)
+    if not mask.IsValid():
+        result.AppendMessage("No SPMD execution mask in this frame.")
+        return
+    result.AppendMessage("mask = %s" % format_varying(mask))
+    for v in frame.GetVariables(True, True, False, True):
+        if v.GetName() == MASK_NAME or not is_varying(v):
+            continue
+        m = mask if lane_count(v) == lane_count(mask) else None
+        result.AppendMessage("%s = %s" % (v.GetName(), format_varying(v, m)))
+
+
+def __lldb_init_module(debugger, internal_dict):
+    debugger.HandleCommand(
+        'type summary add -x "^lanes\\.Varying\\[" -F %s.varying_summary' % __name__)
+    debugger.HandleCommand("command script add -f %s.info_spmd spmd" % __name__)